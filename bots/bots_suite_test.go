@@ -0,0 +1,13 @@
+package bots_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBots(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bots Suite")
+}
@@ -0,0 +1,208 @@
+package bots
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// MaxBotCount is the most bots that can be added to a game at once
+const MaxBotCount = 50
+
+// JoinError is returned when a bot fails to join a game; the bots that joined before it keep playing
+type JoinError struct {
+	// BotAddresses are the addresses of the bots that joined the game before the failure
+	BotAddresses []string
+	err          error
+}
+
+func (e *JoinError) Error() string {
+	return e.err.Error()
+}
+
+func (e *JoinError) Unwrap() error {
+	return e.err
+}
+
+// Options describe how added bots behave
+type Options struct {
+	Strategy Strategy
+	// MinDelay is the shortest amount of time that a bot waits after a phase begins before voting
+	MinDelay time.Duration
+	// MaxDelay is the longest amount of time that a bot waits after a phase begins before voting
+	MaxDelay time.Duration
+}
+
+// Manager adds bot players to games and plays on their behalf
+type Manager struct {
+	gameEngine  game.Engine
+	phaseEngine game.PhaseEngine
+	logger      *slog.Logger
+	botSequence atomic.Int64
+	// ctx is cancelled once the manager is stopped, which stops every bot
+	ctx    context.Context
+	stopFn context.CancelFunc
+}
+
+// NewManager builds a manager whose bots play through the given engine, waiting on phases through the given phase engine so that none are missed
+func NewManager(gameEngine game.Engine, phaseEngine game.PhaseEngine, logger *slog.Logger) *Manager {
+	ctx, stopFn := context.WithCancel(context.Background())
+
+	return &Manager{
+		gameEngine:  gameEngine,
+		phaseEngine: phaseEngine,
+		logger:      logger,
+		ctx:         ctx,
+		stopFn:      stopFn,
+	}
+}
+
+// AddBots joins the given number of bots to the game hosted by the given address.
+// The bots play until the game is over or the manager is stopped, so the given context is only used for joining the game.
+// This returns the addresses of the added bots; if one fails to join, this returns the addresses of those that joined before it along with a *JoinError.
+func (m *Manager) AddBots(ctx context.Context, hostAddress string, count int, options *Options) ([]string, error) {
+	if count < 1 || count > MaxBotCount {
+		return nil, fmt.Errorf("between 1 and %d bots can be added at once, not %d", MaxBotCount, count)
+	} else if options.Strategy == nil {
+		return nil, errors.New("a bot strategy must be supplied")
+	} else if options.MinDelay < 0 || options.MaxDelay < options.MinDelay {
+		return nil, fmt.Errorf("invalid bot delay range of %v to %v", options.MinDelay, options.MaxDelay)
+	}
+
+	botAddresses := make([]string, 0, count)
+	for botIndex := 0; botIndex < count; botIndex++ {
+		botNumber := m.botSequence.Add(1)
+		botAddress := fmt.Sprintf("bot%04d", botNumber)
+		if err := m.gameEngine.JoinGame(ctx, hostAddress, botAddress, fmt.Sprintf("Bot %d", botNumber)); err != nil {
+			return botAddresses, &JoinError{
+				BotAddresses: botAddresses,
+				err:          fmt.Errorf("failed to join bot '%s' to game: %w", botAddress, err),
+			}
+		}
+
+		botAddresses = append(botAddresses, botAddress)
+
+		go m.play(hostAddress, botAddress, options)
+	}
+
+	return botAddresses, nil
+}
+
+// play votes on behalf of the given bot until the game is over, the bot can no longer act, or the manager is stopped
+func (m *Manager) play(hostAddress string, botAddress string, options *Options) {
+	ctx := m.ctx
+	logger := m.logger.With("hostAddress", hostAddress, "playerAddress", botAddress)
+
+	if err := m.gameEngine.WaitForGameStart(ctx, hostAddress); err != nil {
+		logWaitFailure(logger, "bot failed to wait for game start", err)
+		return
+	}
+
+	config, err := m.gameEngine.GetGameConfig(ctx, hostAddress)
+	if err != nil {
+		logWaitFailure(logger, "bot failed to get game configuration", err)
+		return
+	}
+
+	currentPhase := config.StartingPhase
+	for phaseNumber := 1; ; phaseNumber++ {
+		select {
+		case <-time.After(options.chooseDelay()):
+		case <-ctx.Done():
+			return
+		}
+
+		// a vote that misses its phase is rejected, and the bot catches up below
+		if err := m.vote(ctx, hostAddress, botAddress, currentPhase, options.Strategy); err != nil {
+			logger.Warn("bot failed to vote", "timeOfDay", currentPhase, "error", err)
+		}
+
+		// waiting by number returns phases that were executed while the bot was voting, so none are missed
+		phaseExecution, err := m.phaseEngine.WaitForPhaseNumber(ctx, hostAddress, phaseNumber)
+		if err != nil {
+			logWaitFailure(logger, "bot failed to wait for phase execution", err)
+			return
+		} else if phaseExecution.PhaseOutcome != game.PhaseOutcomeContinuation {
+			return
+		}
+
+		self, err := m.gameEngine.GetPlayer(ctx, hostAddress, botAddress)
+		if err != nil || self == nil || !self.CanAct() {
+			return
+		}
+
+		switch phaseExecution.CurrentPhase {
+		case game.TimeOfDayDay:
+			currentPhase = game.TimeOfDayNight
+		case game.TimeOfDayNight:
+			currentPhase = game.TimeOfDayDay
+		}
+	}
+}
+
+// Stop stops every bot added by the manager
+func (m *Manager) Stop() {
+	m.stopFn()
+}
+
+func (m *Manager) vote(ctx context.Context, hostAddress string, botAddress string, currentPhase game.TimeOfDay, strategy Strategy) error {
+	view, err := BuildView(ctx, m.gameEngine, hostAddress, botAddress)
+	if err != nil {
+		return err
+	}
+
+	return Vote(ctx, m.gameEngine, hostAddress, view, currentPhase, strategy)
+}
+
+// Vote casts the vote chosen by the given strategy on behalf of the bot described by the given view.
+// Civilians do not vote during the night.
+func Vote(ctx context.Context, gameEngine game.Engine, hostAddress string, view *View, currentPhase game.TimeOfDay, strategy Strategy) error {
+	if !view.Self.CanAct() {
+		return nil
+	}
+
+	switch currentPhase {
+	case game.TimeOfDayDay:
+		if accusedAddress, hasAccused := strategy.ChooseAccusation(view); hasAccused {
+			return gameEngine.AccuseAsMafia(ctx, hostAddress, view.Self.PlayerAddress, accusedAddress)
+		}
+	case game.TimeOfDayNight:
+		if view.Self.PlayerRole != game.PlayerRoleMafia {
+			return nil
+		}
+
+		if victimAddress, hasVictim := strategy.ChooseKill(view); hasVictim {
+			return gameEngine.VoteToKill(ctx, hostAddress, view.Self.PlayerAddress, victimAddress)
+		}
+	}
+
+	return nil
+}
+
+func (o *Options) chooseDelay() time.Duration {
+	if o.MaxDelay <= o.MinDelay {
+		return o.MinDelay
+	}
+
+	return o.MinDelay + time.Duration(rand.Int63n(int64(o.MaxDelay-o.MinDelay)))
+}
+
+// logWaitFailure logs why a bot stopped waiting; the game ending and the server shutting down are expected, so they are not logged as errors
+func logWaitFailure(logger *slog.Logger, message string, err error) {
+	switch {
+	case errors.Is(err, game.ErrShuttingDown):
+		logger.Info("bot stopped playing because the server is shutting down")
+	case errors.Is(err, game.ErrNotFound):
+		logger.Info("bot stopped playing because the game has ended")
+	case errors.Is(err, context.Canceled):
+		logger.Info("bot stopped playing because the bots were stopped")
+	default:
+		logger.Error(message, "error", err)
+	}
+}
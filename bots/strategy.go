@@ -0,0 +1,212 @@
+package bots
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// View is everything that a bot is allowed to know about a game when deciding how to vote
+type View struct {
+	Self *game.Player
+	// Candidates are the other players in the game who can still act
+	Candidates []*game.Player
+	// Teammates are the addresses of the bot's fellow Mafia members; this is empty if the bot is a civilian
+	Teammates map[string]bool
+	// MafiaAccusations are the accusations made so far during the current day, keyed by accuser
+	MafiaAccusations map[string]string
+	// KillVotes are the votes to kill made so far during the current night, keyed by voter; this is empty if the bot is a civilian
+	KillVotes map[string]string
+}
+
+// BuildView builds what the given bot is allowed to know about the current state of the given game
+func BuildView(ctx context.Context, gameEngine game.Engine, hostAddress string, botAddress string) (*View, error) {
+	self, err := gameEngine.GetPlayer(ctx, hostAddress, botAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bot '%s': %w", botAddress, err)
+	} else if self == nil {
+		return nil, fmt.Errorf("bot '%s' is not a member of the game", botAddress)
+	}
+
+	players, err := gameEngine.GetPlayers(ctx, hostAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get players: %w", err)
+	}
+
+	votes, err := gameEngine.GetVotes(ctx, hostAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get votes: %w", err)
+	}
+
+	view := &View{
+		Self:             self,
+		Teammates:        make(map[string]bool),
+		MafiaAccusations: votes.MafiaAccusations,
+		KillVotes:        make(map[string]string),
+	}
+
+	isMafia := self.PlayerRole == game.PlayerRoleMafia
+	if isMafia {
+		view.KillVotes = votes.KillVotes
+	}
+
+	for _, player := range players {
+		if player.PlayerAddress == self.PlayerAddress || !player.CanAct() {
+			continue
+		}
+
+		// only the Mafia know who is in the Mafia
+		if isMafia && player.PlayerRole == game.PlayerRoleMafia {
+			view.Teammates[player.PlayerAddress] = true
+		}
+
+		view.Candidates = append(view.Candidates, &game.Player{
+			PlayerAddress:  player.PlayerAddress,
+			PlayerNickname: player.PlayerNickname,
+		})
+	}
+
+	sort.Slice(view.Candidates, func(a, b int) bool {
+		return view.Candidates[a].PlayerAddress < view.Candidates[b].PlayerAddress
+	})
+
+	return view, nil
+}
+
+// Strategy decides how a bot votes
+type Strategy interface {
+	// ChooseAccusation chooses whom the bot accuses of being in the Mafia during the day.
+	// This returns false if the bot chooses not to accuse anyone.
+	ChooseAccusation(view *View) (string, bool)
+	// ChooseKill chooses whom the bot, as a member of the Mafia, votes to kill during the night.
+	// This returns false if the bot chooses not to vote.
+	ChooseKill(view *View) (string, bool)
+}
+
+// StrategyNames are the names of all of the strategies that can be built by NewStrategy
+var StrategyNames = []string{"random", "crowd", "mafia"}
+
+// NewStrategy builds the strategy with the given name
+func NewStrategy(name string) (Strategy, error) {
+	switch name {
+	case "random":
+		return &RandomStrategy{}, nil
+	case "crowd":
+		return &CrowdStrategy{}, nil
+	case "mafia":
+		return &MafiaCoordinatedStrategy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown bot strategy: '%s'", name)
+	}
+}
+
+// RandomStrategy votes for a random player
+type RandomStrategy struct{}
+
+func (*RandomStrategy) ChooseAccusation(view *View) (string, bool) {
+	return chooseRandom(nonTeammates(view))
+}
+
+func (*RandomStrategy) ChooseKill(view *View) (string, bool) {
+	return chooseRandom(nonTeammates(view))
+}
+
+// CrowdStrategy votes for whomever has received the most votes so far, falling back to a random player if no one has been voted for
+type CrowdStrategy struct{}
+
+func (*CrowdStrategy) ChooseAccusation(view *View) (string, bool) {
+	if candidate, hasCandidate := chooseMostVoted(view, view.MafiaAccusations); hasCandidate {
+		return candidate, true
+	}
+
+	return chooseRandom(nonTeammates(view))
+}
+
+func (*CrowdStrategy) ChooseKill(view *View) (string, bool) {
+	if candidate, hasCandidate := chooseMostVoted(view, view.KillVotes); hasCandidate {
+		return candidate, true
+	}
+
+	return chooseRandom(nonTeammates(view))
+}
+
+// MafiaCoordinatedStrategy has all Mafia members vote for the same civilian both day and night.
+// Civilians using this strategy vote randomly.
+type MafiaCoordinatedStrategy struct{}
+
+func (s *MafiaCoordinatedStrategy) ChooseAccusation(view *View) (string, bool) {
+	if view.Self.PlayerRole != game.PlayerRoleMafia {
+		return chooseRandom(nonTeammates(view))
+	}
+
+	return s.chooseSharedTarget(view, view.MafiaAccusations)
+}
+
+func (s *MafiaCoordinatedStrategy) ChooseKill(view *View) (string, bool) {
+	return s.chooseSharedTarget(view, view.KillVotes)
+}
+
+// chooseSharedTarget picks the target already chosen by a fellow Mafia member or, if none has chosen one, a target that every Mafia member would pick
+func (*MafiaCoordinatedStrategy) chooseSharedTarget(view *View, votes map[string]string) (string, bool) {
+	candidates := nonTeammates(view)
+
+	for _, candidate := range candidates {
+		for voterAddress, votedAddress := range votes {
+			if view.Teammates[voterAddress] && votedAddress == candidate {
+				return candidate, true
+			}
+		}
+	}
+
+	// candidates are sorted by address, so every Mafia member will agree on the first one
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	return candidates[0], true
+}
+
+func chooseMostVoted(view *View, votes map[string]string) (string, bool) {
+	voteCounts := make(map[string]int)
+	for _, votedAddress := range votes {
+		voteCounts[votedAddress]++
+	}
+
+	var mostVoted []string
+	var highestVoteCount int
+	for _, candidate := range nonTeammates(view) {
+		switch voteCount := voteCounts[candidate]; {
+		case voteCount == 0:
+			continue
+		case voteCount > highestVoteCount:
+			highestVoteCount = voteCount
+			mostVoted = []string{candidate}
+		case voteCount == highestVoteCount:
+			mostVoted = append(mostVoted, candidate)
+		}
+	}
+
+	return chooseRandom(mostVoted)
+}
+
+func chooseRandom(candidates []string) (string, bool) {
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	return candidates[rand.Intn(len(candidates))], true
+}
+
+// nonTeammates gets the addresses of all candidates who are not known to be in the Mafia alongside the bot
+func nonTeammates(view *View) []string {
+	var addresses []string
+	for _, candidate := range view.Candidates {
+		if !view.Teammates[candidate.PlayerAddress] {
+			addresses = append(addresses, candidate.PlayerAddress)
+		}
+	}
+	return addresses
+}
@@ -0,0 +1,162 @@
+package bots_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jrh3k5/mafia-dapp-http/bots"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+var _ = Describe("Strategy", func() {
+	// buildView builds the view of the given bot among the given candidates, who are listed by address as BuildView lists them
+	buildView := func(selfAddress string, selfRole game.PlayerRole, candidateAddresses []string, teammateAddresses ...string) *bots.View {
+		view := &bots.View{
+			Self: &game.Player{
+				PlayerAddress: selfAddress,
+				PlayerRole:    selfRole,
+			},
+			Teammates:        make(map[string]bool),
+			MafiaAccusations: make(map[string]string),
+			KillVotes:        make(map[string]string),
+		}
+		for _, candidateAddress := range candidateAddresses {
+			view.Candidates = append(view.Candidates, &game.Player{PlayerAddress: candidateAddress})
+		}
+		for _, teammateAddress := range teammateAddresses {
+			view.Teammates[teammateAddress] = true
+		}
+		return view
+	}
+
+	// chooseMany has the given strategy choose enough times that any candidate it could choose is very likely to be chosen
+	chooseMany := func(choose func(view *bots.View) (string, bool), view *bots.View) map[string]bool {
+		chosen := make(map[string]bool)
+		for choiceIndex := 0; choiceIndex < 200; choiceIndex++ {
+			target, hasTarget := choose(view)
+			Expect(hasTarget).To(BeTrue(), "the strategy should choose a target")
+			chosen[target] = true
+		}
+		return chosen
+	}
+
+	// strategyChoice is one of the choices that a strategy makes
+	type strategyChoice struct {
+		choose func(strategy bots.Strategy) func(view *bots.View) (string, bool)
+	}
+	accusation := strategyChoice{func(strategy bots.Strategy) func(view *bots.View) (string, bool) {
+		return strategy.ChooseAccusation
+	}}
+	kill := strategyChoice{func(strategy bots.Strategy) func(view *bots.View) (string, bool) {
+		return strategy.ChooseKill
+	}}
+
+	DescribeTable("never targets the bot or its teammates",
+		func(strategyName string, choice strategyChoice) {
+			strategy, err := bots.NewStrategy(strategyName)
+			Expect(err).ToNot(HaveOccurred(), "building the strategy should not fail")
+
+			view := buildView("mafia1", game.PlayerRoleMafia, []string{"civilian1", "civilian2", "mafia2", "mafia3"}, "mafia2", "mafia3")
+			// teammates voting for each other should not lead the bot to follow them
+			view.MafiaAccusations["mafia2"] = "mafia3"
+			view.KillVotes["mafia3"] = "mafia2"
+
+			Expect(chooseMany(choice.choose(strategy), view)).To(And(
+				Not(HaveKey("mafia1")),
+				Not(HaveKey("mafia2")),
+				Not(HaveKey("mafia3")),
+			), "the %s strategy should only target civilians", strategyName)
+		},
+		Entry("random accusation", "random", accusation),
+		Entry("random kill", "random", kill),
+		Entry("crowd accusation", "crowd", accusation),
+		Entry("crowd kill", "crowd", kill),
+		Entry("mafia accusation", "mafia", accusation),
+		Entry("mafia kill", "mafia", kill),
+	)
+
+	DescribeTable("chooses no one when there is no one to target",
+		func(strategyName string, choice strategyChoice) {
+			strategy, err := bots.NewStrategy(strategyName)
+			Expect(err).ToNot(HaveOccurred(), "building the strategy should not fail")
+
+			_, hasTarget := choice.choose(strategy)(buildView("mafia1", game.PlayerRoleMafia, []string{"mafia2"}, "mafia2"))
+			Expect(hasTarget).To(BeFalse(), "the %s strategy should not choose a target when only teammates remain", strategyName)
+		},
+		Entry("random accusation", "random", accusation),
+		Entry("random kill", "random", kill),
+		Entry("crowd accusation", "crowd", accusation),
+		Entry("crowd kill", "crowd", kill),
+		Entry("mafia accusation", "mafia", accusation),
+		Entry("mafia kill", "mafia", kill),
+	)
+
+	DescribeTable("selects targets",
+		func(strategy bots.Strategy, choose func(strategy bots.Strategy, view *bots.View) (string, bool), view *bots.View, expectedTargets []string) {
+			chosen := chooseMany(func(view *bots.View) (string, bool) {
+				return choose(strategy, view)
+			}, view)
+
+			var chosenTargets []string
+			for target := range chosen {
+				chosenTargets = append(chosenTargets, target)
+			}
+			Expect(chosenTargets).To(ConsistOf(expectedTargets), "unexpected targets chosen")
+		},
+		Entry("random picks any civilian",
+			&bots.RandomStrategy{}, bots.Strategy.ChooseAccusation,
+			buildView("civilian1", game.PlayerRoleCivilian, []string{"civilian2", "civilian3", "mafia1"}),
+			[]string{"civilian2", "civilian3", "mafia1"}),
+		Entry("crowd follows the most accused player",
+			&bots.CrowdStrategy{}, bots.Strategy.ChooseAccusation,
+			func() *bots.View {
+				view := buildView("civilian1", game.PlayerRoleCivilian, []string{"civilian2", "civilian3", "mafia1"})
+				view.MafiaAccusations["civilian2"] = "mafia1"
+				view.MafiaAccusations["civilian3"] = "mafia1"
+				view.MafiaAccusations["mafia1"] = "civilian2"
+				return view
+			}(),
+			[]string{"mafia1"}),
+		Entry("crowd splits between players tied for the most votes",
+			&bots.CrowdStrategy{}, bots.Strategy.ChooseKill,
+			func() *bots.View {
+				view := buildView("mafia1", game.PlayerRoleMafia, []string{"civilian1", "civilian2", "civilian3", "mafia2", "mafia3"}, "mafia2", "mafia3")
+				view.KillVotes["mafia2"] = "civilian1"
+				view.KillVotes["mafia3"] = "civilian3"
+				return view
+			}(),
+			[]string{"civilian1", "civilian3"}),
+		Entry("crowd picks any civilian before anyone has voted",
+			&bots.CrowdStrategy{}, bots.Strategy.ChooseKill,
+			buildView("mafia1", game.PlayerRoleMafia, []string{"civilian1", "civilian2", "mafia2"}, "mafia2"),
+			[]string{"civilian1", "civilian2"}),
+		Entry("coordinated Mafia agree on the first civilian by address",
+			&bots.MafiaCoordinatedStrategy{}, bots.Strategy.ChooseKill,
+			buildView("mafia1", game.PlayerRoleMafia, []string{"civilian1", "civilian2", "mafia2"}, "mafia2"),
+			[]string{"civilian1"}),
+		Entry("coordinated Mafia follow the target of a teammate",
+			&bots.MafiaCoordinatedStrategy{}, bots.Strategy.ChooseAccusation,
+			func() *bots.View {
+				view := buildView("mafia1", game.PlayerRoleMafia, []string{"civilian1", "civilian2", "mafia2"}, "mafia2")
+				view.MafiaAccusations["mafia2"] = "civilian2"
+				// a civilian's accusation should not sway the Mafia
+				view.MafiaAccusations["civilian2"] = "civilian1"
+				return view
+			}(),
+			[]string{"civilian2"}),
+		Entry("coordinated civilians vote randomly",
+			&bots.MafiaCoordinatedStrategy{}, bots.Strategy.ChooseAccusation,
+			buildView("civilian1", game.PlayerRoleCivilian, []string{"civilian2", "mafia1"}),
+			[]string{"civilian2", "mafia1"}),
+	)
+
+	It("builds every named strategy", func() {
+		for _, strategyName := range bots.StrategyNames {
+			_, err := bots.NewStrategy(strategyName)
+			Expect(err).ToNot(HaveOccurred(), "building the '%s' strategy should not fail", strategyName)
+		}
+
+		_, err := bots.NewStrategy("cunning")
+		Expect(err).To(MatchError(ContainSubstring("unknown bot strategy")), "unknown strategies should be rejected")
+	})
+})
@@ -0,0 +1,135 @@
+// Package client calls a running server over HTTP.
+// Client satisfies game.Engine, so that anything written against the engine, such as the scenario runner, can be pointed at a remote server.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// Options configures how a client calls the server
+type Options struct {
+	// MaxRetries is the number of times that a read, such as a long poll, is retried after its connection is dropped or the server is briefly unavailable.
+	// Requests that change a game are never retried, as they may have taken effect before the connection was dropped.
+	MaxRetries int
+	// RetryWait is how long to wait before retrying a read
+	RetryWait time.Duration
+	// AdminToken is sent as a bearer token with requests to the admin endpoints, such as those made by GetVotes; it is never sent to any other route
+	AdminToken string
+}
+
+// DefaultOptions builds the options used when a client is built without any
+func DefaultOptions() *Options {
+	return &Options{
+		MaxRetries: 3,
+		RetryWait:  500 * time.Millisecond,
+	}
+}
+
+// Client calls the server at a base URL
+type Client struct {
+	baseURL string
+	client  *resty.Client
+	options *Options
+}
+
+var _ game.Engine = (*Client)(nil)
+
+// New builds a client of the server at the given base URL (e.g., "http://localhost:3000"); if no options are given, then the default options are used
+func New(baseURL string, options *Options) *Client {
+	if options == nil {
+		options = DefaultOptions()
+	}
+
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  resty.New(),
+		options: options,
+	}
+}
+
+// get sends a GET request, retrying it if its connection is dropped, and unmarshals the response body into the given result
+func (c *Client) get(ctx context.Context, path string, query url.Values, result any) error {
+	for attempt := 0; ; attempt++ {
+		err := c.send(ctx, http.MethodGet, path, query, nil, result)
+		if err == nil || attempt >= c.options.MaxRetries || !isRetryable(ctx, err) {
+			return err
+		}
+
+		select {
+		case <-time.After(c.options.RetryWait):
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+}
+
+// send sends a request and, if a result is given, unmarshals the response body into it
+func (c *Client) send(ctx context.Context, method string, path string, query url.Values, body any, result any) error {
+	request := c.client.R().SetContext(ctx).SetQueryParamsFromValues(query)
+	if body != nil {
+		request = request.SetBody(body)
+	}
+	if c.options.AdminToken != "" && strings.HasPrefix(path, "/admin/") {
+		request = request.SetAuthToken(c.options.AdminToken)
+	}
+
+	response, err := request.Execute(method, c.baseURL+path)
+	if err != nil {
+		return fmt.Errorf("failed to send %s request to '%s': %w", method, path, err)
+	}
+
+	if response.IsError() {
+		return newError(response.StatusCode(), response.Body())
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(response.Body(), result); err != nil {
+			return fmt.Errorf("failed to parse response to %s request to '%s': %w", method, path, err)
+		}
+	}
+
+	return nil
+}
+
+// isRetryable determines whether a failed read is worth retrying
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var clientErr *Error
+	if errors.As(err, &clientErr) {
+		switch clientErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		default:
+			return false
+		}
+	}
+
+	// the request never got a response, such as when a long poll is dropped
+	return true
+}
+
+// gamePath builds the path to the given resource within the game hosted by the given address
+func gamePath(hostAddress string, segments ...string) string {
+	return buildPath(append([]string{"game", hostAddress}, segments...)...)
+}
+
+func buildPath(segments ...string) string {
+	escapedSegments := make([]string, len(segments))
+	for segmentIndex, segment := range segments {
+		escapedSegments[segmentIndex] = url.PathEscape(segment)
+	}
+	return "/" + strings.Join(escapedSegments, "/")
+}
@@ -0,0 +1,291 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+func (c *Client) AccuseAsMafia(ctx context.Context, hostAddress string, accuserAddress string, accuseeAddress string) error {
+	query := url.Values{
+		"playerAddress": {accuseeAddress},
+	}
+	return c.send(ctx, http.MethodPost, gamePath(hostAddress, "players", accuserAddress, "vote", "accuse"), query, nil, nil)
+}
+
+func (c *Client) AddSpectator(ctx context.Context, hostAddress string, spectatorAddress string, godView bool) error {
+	query := url.Values{
+		"spectatorAddress": {spectatorAddress},
+		"godView":          {strconv.FormatBool(godView)},
+	}
+	return c.send(ctx, http.MethodPost, gamePath(hostAddress, "spectators"), query, nil, nil)
+}
+
+func (c *Client) CancelGame(ctx context.Context, hostAddress string) error {
+	return c.send(ctx, http.MethodDelete, gamePath(hostAddress), nil, nil, nil)
+}
+
+func (c *Client) ExecutePhase(ctx context.Context, hostAddress string) (*game.PhaseExecution, error) {
+	var response phaseExecutionResponse
+	if err := c.send(ctx, http.MethodPost, gamePath(hostAddress, "phase", "execute"), nil, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.toPhaseExecution(), nil
+}
+
+func (c *Client) FinishGame(ctx context.Context, hostAddress string) error {
+	return c.send(ctx, http.MethodPost, gamePath(hostAddress, "finish"), nil, nil, nil)
+}
+
+// GetArchivedGame gets the full record of a finished or cancelled game; nil is returned if there is no such game
+func (c *Client) GetArchivedGame(ctx context.Context, gameID string) (*game.ArchivedGame, error) {
+	var response archivedGameResponse
+	if err := c.get(ctx, buildPath("archive", "games", gameID), nil, &response); err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return response.toArchivedGame(), nil
+}
+
+// GetArchivedGames gets the full record of every finished and cancelled game.
+// The server only lists summaries of the games, so each game is fetched individually.
+func (c *Client) GetArchivedGames(ctx context.Context) ([]*game.ArchivedGame, error) {
+	var summaries []*archivedGameSummaryResponse
+	if err := c.get(ctx, buildPath("archive", "games"), nil, &summaries); err != nil {
+		return nil, err
+	}
+
+	archivedGames := make([]*game.ArchivedGame, 0, len(summaries))
+	for _, summary := range summaries {
+		archivedGame, err := c.GetArchivedGame(ctx, summary.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if archivedGame != nil {
+			archivedGames = append(archivedGames, archivedGame)
+		}
+	}
+
+	return archivedGames, nil
+}
+
+func (c *Client) GetChatMessages(ctx context.Context, hostAddress string, readerAddress string, channel game.ChatChannel, afterSequence int) ([]*game.ChatMessage, error) {
+	var responses []*chatMessageResponse
+	if err := c.get(ctx, gamePath(hostAddress, "chat", string(channel)), chatQuery(readerAddress, afterSequence), &responses); err != nil {
+		return nil, err
+	}
+
+	return toChatMessages(responses), nil
+}
+
+// GetGameConfig gets the configuration of a game.
+// The server does not reveal forced roles, so they are never included.
+func (c *Client) GetGameConfig(ctx context.Context, hostAddress string) (*game.GameConfig, error) {
+	var response gameConfigDocument
+	if err := c.get(ctx, gamePath(hostAddress, "config"), nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.toGameConfig(), nil
+}
+
+func (c *Client) GetLobby(ctx context.Context) (*game.Lobby, error) {
+	var response lobbyResponse
+	if err := c.get(ctx, buildPath("games"), nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.toLobby(), nil
+}
+
+// GetPlayer gets a player, including their role; nil is returned if the player is not a member of the game
+func (c *Client) GetPlayer(ctx context.Context, hostAddress string, playerAddress string) (*game.Player, error) {
+	var response playerResponse
+	if err := c.get(ctx, gamePath(hostAddress, "players", playerAddress), nil, &response); err != nil {
+		return nil, c.ignoreMissingMember(ctx, hostAddress, err)
+	}
+
+	return response.toPlayer(), nil
+}
+
+func (c *Client) GetPlayerStats(ctx context.Context, playerAddress string) (*game.PlayerStats, error) {
+	var response playerStatsResponse
+	if err := c.get(ctx, buildPath("players", playerAddress, "stats"), nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &game.PlayerStats{
+		PlayerAddress:      response.PlayerAddress,
+		GamesPlayed:        response.GamesPlayed,
+		CivilianWins:       response.CivilianWins,
+		MafiaWins:          response.MafiaWins,
+		TimesConvicted:     response.TimesConvicted,
+		TimesKilled:        response.TimesKilled,
+		Accusations:        response.Accusations,
+		CorrectAccusations: response.CorrectAccusations,
+	}, nil
+}
+
+// GetPlayers gets every player in a game, including their roles.
+// The server hides roles when listing players, so each player is fetched individually.
+func (c *Client) GetPlayers(ctx context.Context, hostAddress string) ([]*game.Player, error) {
+	listedPlayers, err := c.ListPlayers(ctx, hostAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	players := make([]*game.Player, 0, len(listedPlayers))
+	for _, listedPlayer := range listedPlayers {
+		player, err := c.GetPlayer(ctx, hostAddress, listedPlayer.PlayerAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		// the player may have left since the game was listed
+		if player != nil {
+			players = append(players, player)
+		}
+	}
+
+	return players, nil
+}
+
+// GetSpectator gets how a spectator is watching a game; nil is returned if they are not spectating the game
+func (c *Client) GetSpectator(ctx context.Context, hostAddress string, spectatorAddress string) (*game.Spectator, error) {
+	var response spectatorResponse
+	if err := c.get(ctx, gamePath(hostAddress, "spectators", spectatorAddress), nil, &response); err != nil {
+		return nil, c.ignoreMissingMember(ctx, hostAddress, err)
+	}
+
+	return &game.Spectator{
+		SpectatorAddress: response.SpectatorAddress,
+		GodView:          response.GodView,
+	}, nil
+}
+
+// GetVotes gets the votes cast so far in the current phase of a game.
+// The votes are only available to anyone other than a spectator through the admin endpoints, so the server must be running in dev mode and the client must be given its admin token.
+func (c *Client) GetVotes(ctx context.Context, hostAddress string) (*game.Votes, error) {
+	var response votesResponse
+	if err := c.get(ctx, buildPath("admin", "game", hostAddress, "votes"), nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &game.Votes{
+		MafiaAccusations: response.MafiaAccusations,
+		KillVotes:        response.KillVotes,
+	}, nil
+}
+
+// InitializeGame initializes a game; if no configuration is given, then the server's default configuration is used
+func (c *Client) InitializeGame(ctx context.Context, hostAddress string, config *game.GameConfig) error {
+	var body any
+	if config != nil {
+		body = toGameConfigDocument(config)
+	}
+	return c.send(ctx, http.MethodPost, gamePath(hostAddress), nil, body, nil)
+}
+
+func (c *Client) JoinGame(ctx context.Context, hostAddress string, playerAddress string, playerNickname string) error {
+	query := url.Values{
+		"playerAddress":  {playerAddress},
+		"playerNickname": {playerNickname},
+	}
+	return c.send(ctx, http.MethodPost, gamePath(hostAddress, "join"), query, nil, nil)
+}
+
+func (c *Client) LeaveGame(ctx context.Context, hostAddress string, requesterAddress string, playerAddress string) error {
+	query := url.Values{
+		"requesterAddress": {requesterAddress},
+	}
+	return c.send(ctx, http.MethodDelete, gamePath(hostAddress, "players", playerAddress), query, nil, nil)
+}
+
+func (c *Client) PostChatMessage(ctx context.Context, hostAddress string, senderAddress string, channel game.ChatChannel, message string) (*game.ChatMessage, error) {
+	query := url.Values{
+		"playerAddress": {senderAddress},
+	}
+
+	var response chatMessageResponse
+	if err := c.send(ctx, http.MethodPost, gamePath(hostAddress, "chat", string(channel)), query, &chatMessageRequest{Message: message}, &response); err != nil {
+		return nil, err
+	}
+
+	return response.toChatMessage(), nil
+}
+
+func (c *Client) StartGame(ctx context.Context, hostAddress string) error {
+	return c.send(ctx, http.MethodPost, gamePath(hostAddress, "start"), nil, nil, nil)
+}
+
+func (c *Client) VoteToKill(ctx context.Context, hostAddress string, killerAddress string, killeeAddress string) error {
+	query := url.Values{
+		"playerAddress": {killeeAddress},
+	}
+	return c.send(ctx, http.MethodPost, gamePath(hostAddress, "players", killerAddress, "vote", "kill"), query, nil, nil)
+}
+
+func (c *Client) WaitForChatMessages(ctx context.Context, hostAddress string, readerAddress string, channel game.ChatChannel, afterSequence int) ([]*game.ChatMessage, error) {
+	var responses []*chatMessageResponse
+	if err := c.get(ctx, gamePath(hostAddress, "chat", string(channel), "wait"), chatQuery(readerAddress, afterSequence), &responses); err != nil {
+		return nil, err
+	}
+
+	return toChatMessages(responses), nil
+}
+
+func (c *Client) WaitForGameStart(ctx context.Context, hostAddress string) error {
+	return c.get(ctx, gamePath(hostAddress, "start", "wait"), nil, nil)
+}
+
+func (c *Client) WaitForLobbyUpdate(ctx context.Context, sinceVersion int) (*game.Lobby, error) {
+	query := url.Values{
+		"version": {strconv.Itoa(sinceVersion)},
+	}
+
+	var response lobbyResponse
+	if err := c.get(ctx, buildPath("games", "wait"), query, &response); err != nil {
+		return nil, err
+	}
+
+	return response.toLobby(), nil
+}
+
+func (c *Client) WaitForPhaseExecution(ctx context.Context, hostAddress string) (*game.PhaseExecution, error) {
+	var response phaseExecutionResponse
+	if err := c.get(ctx, gamePath(hostAddress, "phase", "wait"), nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.toPhaseExecution(), nil
+}
+
+// ignoreMissingMember discards an error caused by a player or spectator not being part of a game, as the engine signals that by returning nil rather than an error.
+// The server responds the same way when the game itself is missing, so the game is looked up to tell the two apart.
+func (c *Client) ignoreMissingMember(ctx context.Context, hostAddress string, err error) error {
+	if !errors.Is(err, game.ErrNotFound) {
+		return err
+	}
+
+	if _, configErr := c.GetGameConfig(ctx, hostAddress); configErr != nil {
+		return configErr
+	}
+
+	return nil
+}
+
+func chatQuery(readerAddress string, afterSequence int) url.Values {
+	return url.Values{
+		"playerAddress": {readerAddress},
+		"after":         {strconv.Itoa(afterSequence)},
+	}
+}
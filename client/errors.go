@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// Error is returned when the server rejects a request.
+// It matches the game package's sentinel error for its code, so that failures can be told apart the same way as those of an in-process engine (e.g., errors.Is(err, game.ErrConflict)).
+type Error struct {
+	StatusCode int
+	// Code identifies the kind of failure (e.g., "not_found"); it is empty if the server did not describe the failure
+	Code    string
+	Message string
+	// PlayersNeeded is the number of additional players that must join before the game can be started, if that is why the request was rejected
+	PlayersNeeded int
+}
+
+func newError(statusCode int, body []byte) *Error {
+	clientErr := &Error{
+		StatusCode: statusCode,
+	}
+
+	var response errorResponse
+	if err := json.Unmarshal(body, &response); err == nil && response.Message != "" {
+		clientErr.Code = response.Code
+		clientErr.Message = response.Message
+		clientErr.PlayersNeeded = response.PlayersNeeded
+	} else if trimmedBody := strings.TrimSpace(string(body)); trimmedBody != "" {
+		clientErr.Message = trimmedBody
+	} else {
+		clientErr.Message = http.StatusText(statusCode)
+	}
+
+	return clientErr
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("server responded with status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("server responded with status %d (%s): %s", e.StatusCode, e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	switch e.Code {
+	case "not_found":
+		return game.ErrNotFound
+	case "conflict":
+		return game.ErrConflict
+	case "forbidden", "unauthorized":
+		// an admin token that is missing or wrong keeps the caller from the admin endpoints just as a rule of the game would
+		return game.ErrForbidden
+	case "invalid_phase":
+		return game.ErrInvalidPhase
+	case "invalid_target":
+		return game.ErrInvalidTarget
+	case "invalid_request":
+		return game.ErrInvalidRequest
+	case "shutting_down":
+		return game.ErrShuttingDown
+	case "wait_timeout":
+		// the same as an in-process engine whose wait reaches its deadline
+		return context.DeadlineExceeded
+	}
+
+	// routes that do not exist, such as the admin routes of a server not in dev mode, are not described
+	if e.StatusCode == http.StatusNotFound {
+		return game.ErrNotFound
+	}
+
+	return nil
+}
+
+type errorResponse struct {
+	Code          string `json:"code"`
+	Message       string `json:"message"`
+	PlayersNeeded int    `json:"playersNeeded"`
+}
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// ListedPlayer is a player as everyone in a game sees them, which only includes their role if it has been revealed
+type ListedPlayer struct {
+	*game.Player
+	// RoleRevealed indicates that PlayerRole is the role of the player, such as when the game reveals the roles of dead players; otherwise, it is left as the zero role
+	RoleRevealed bool
+}
+
+// ListPlayers lists the players in a game as everyone in it sees them.
+// Unlike GetPlayers, this does not look up each player, so it takes a single request but only includes the roles that have been revealed.
+func (c *Client) ListPlayers(ctx context.Context, hostAddress string) ([]*ListedPlayer, error) {
+	var responses []*playerResponse
+	if err := c.get(ctx, gamePath(hostAddress, "players"), nil, &responses); err != nil {
+		return nil, err
+	}
+
+	listedPlayers := make([]*ListedPlayer, len(responses))
+	for responseIndex, response := range responses {
+		listedPlayers[responseIndex] = &ListedPlayer{
+			Player:       response.toPlayer(),
+			RoleRevealed: response.PlayerRole != nil,
+		}
+	}
+
+	return listedPlayers, nil
+}
@@ -0,0 +1,283 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// ParseGameConfig reads a game configuration document in the form accepted by the server.
+// Any settings not given in the document are left at their default values.
+func ParseGameConfig(document []byte) (*game.GameConfig, error) {
+	parsedDocument := toGameConfigDocument(game.DefaultGameConfig())
+	if len(bytes.TrimSpace(document)) == 0 {
+		return parsedDocument.toGameConfig(), nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	// reject typos rather than silently falling back to defaults
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(parsedDocument); err != nil {
+		return nil, fmt.Errorf("invalid game configuration document: %w", err)
+	}
+
+	if decoder.More() {
+		return nil, errors.New("invalid game configuration document: only a single JSON object is allowed")
+	}
+
+	return parsedDocument.toGameConfig(), nil
+}
+
+func toGameConfigDocument(config *game.GameConfig) *gameConfigDocument {
+	var forcedRoles map[string]int
+	if len(config.ForcedRoles) > 0 {
+		forcedRoles = make(map[string]int, len(config.ForcedRoles))
+		for playerAddress, playerRole := range config.ForcedRoles {
+			forcedRoles[playerAddress] = int(playerRole)
+		}
+	}
+
+	return &gameConfigDocument{
+		Private:              config.Private,
+		MinPlayers:           config.MinPlayers,
+		MaxPlayers:           config.MaxPlayers,
+		MafiaCount:           config.MafiaCount,
+		MafiaRatio:           config.MafiaRatio,
+		TiePolicy:            string(config.TiePolicy),
+		DayDurationSeconds:   int(config.DayDuration / time.Second),
+		NightDurationSeconds: int(config.NightDuration / time.Second),
+		StartingPhase:        int(config.StartingPhase),
+		RevealRoleOnDeath:    config.RevealRoleOnDeath,
+		AllowGodView:         config.AllowGodView,
+		ForcedRoles:          forcedRoles,
+	}
+}
+
+type gameConfigDocument struct {
+	Private              bool           `json:"private"`
+	MinPlayers           int            `json:"minPlayers"`
+	MaxPlayers           int            `json:"maxPlayers"`
+	MafiaCount           int            `json:"mafiaCount"`
+	MafiaRatio           int            `json:"mafiaRatio"`
+	TiePolicy            string         `json:"tiePolicy"`
+	DayDurationSeconds   int            `json:"dayDurationSeconds"`
+	NightDurationSeconds int            `json:"nightDurationSeconds"`
+	StartingPhase        int            `json:"startingPhase"`
+	RevealRoleOnDeath    bool           `json:"revealRoleOnDeath"`
+	AllowGodView         bool           `json:"allowGodView"`
+	ForcedRoles          map[string]int `json:"forcedRoles,omitempty"`
+}
+
+func (d *gameConfigDocument) toGameConfig() *game.GameConfig {
+	var forcedRoles map[string]game.PlayerRole
+	if len(d.ForcedRoles) > 0 {
+		forcedRoles = make(map[string]game.PlayerRole, len(d.ForcedRoles))
+		for playerAddress, playerRole := range d.ForcedRoles {
+			forcedRoles[playerAddress] = game.PlayerRole(playerRole)
+		}
+	}
+
+	return &game.GameConfig{
+		Private:           d.Private,
+		MinPlayers:        d.MinPlayers,
+		MaxPlayers:        d.MaxPlayers,
+		MafiaCount:        d.MafiaCount,
+		MafiaRatio:        d.MafiaRatio,
+		TiePolicy:         game.TiePolicy(d.TiePolicy),
+		DayDuration:       time.Duration(d.DayDurationSeconds) * time.Second,
+		NightDuration:     time.Duration(d.NightDurationSeconds) * time.Second,
+		StartingPhase:     game.TimeOfDay(d.StartingPhase),
+		RevealRoleOnDeath: d.RevealRoleOnDeath,
+		AllowGodView:      d.AllowGodView,
+		ForcedRoles:       forcedRoles,
+	}
+}
+
+type archivedGameSummaryResponse struct {
+	ID           string     `json:"id"`
+	HostAddress  string     `json:"hostAddress"`
+	Cancelled    bool       `json:"cancelled"`
+	PhaseOutcome int        `json:"phaseOutcome"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	StartedAt    *time.Time `json:"startedAt"`
+	EndedAt      time.Time  `json:"endedAt"`
+}
+
+type archivedGameResponse struct {
+	archivedGameSummaryResponse
+	Players         []*playerResponse         `json:"players"`
+	PhaseExecutions []*phaseExecutionResponse `json:"phaseExecutions"`
+}
+
+func (r *archivedGameResponse) toArchivedGame() *game.ArchivedGame {
+	archivedGame := &game.ArchivedGame{
+		ID:              r.ID,
+		HostAddress:     r.HostAddress,
+		Cancelled:       r.Cancelled,
+		PhaseOutcome:    game.PhaseOutcome(r.PhaseOutcome),
+		Players:         make([]*game.Player, len(r.Players)),
+		PhaseExecutions: make([]*game.PhaseExecution, len(r.PhaseExecutions)),
+		CreatedAt:       r.CreatedAt,
+		EndedAt:         r.EndedAt,
+	}
+
+	if r.StartedAt != nil {
+		archivedGame.StartedAt = *r.StartedAt
+	}
+
+	for playerIndex, player := range r.Players {
+		archivedGame.Players[playerIndex] = player.toPlayer()
+	}
+
+	for phaseIndex, phaseExecution := range r.PhaseExecutions {
+		archivedGame.PhaseExecutions[phaseIndex] = phaseExecution.toPhaseExecution()
+	}
+
+	return archivedGame
+}
+
+type chatMessageRequest struct {
+	Message string `json:"message"`
+}
+
+type chatMessageResponse struct {
+	Sequence       int       `json:"sequence"`
+	Channel        string    `json:"channel"`
+	SenderAddress  string    `json:"senderAddress"`
+	SenderNickname string    `json:"senderNickname"`
+	Message        string    `json:"message"`
+	TimeOfDay      int       `json:"timeOfDay"`
+	SentAt         time.Time `json:"sentAt"`
+}
+
+func (r *chatMessageResponse) toChatMessage() *game.ChatMessage {
+	return &game.ChatMessage{
+		Sequence:       r.Sequence,
+		Channel:        game.ChatChannel(r.Channel),
+		SenderAddress:  r.SenderAddress,
+		SenderNickname: r.SenderNickname,
+		Message:        r.Message,
+		TimeOfDay:      game.TimeOfDay(r.TimeOfDay),
+		SentAt:         r.SentAt,
+	}
+}
+
+func toChatMessages(responses []*chatMessageResponse) []*game.ChatMessage {
+	messages := make([]*game.ChatMessage, len(responses))
+	for messageIndex, response := range responses {
+		messages[messageIndex] = response.toChatMessage()
+	}
+	return messages
+}
+
+type lobbyResponse struct {
+	Version int                  `json:"version"`
+	Games   []*lobbyGameResponse `json:"games"`
+}
+
+func (r *lobbyResponse) toLobby() *game.Lobby {
+	games := make([]*game.LobbyGame, len(r.Games))
+	for gameIndex, lobbyGame := range r.Games {
+		games[gameIndex] = &game.LobbyGame{
+			HostAddress:  lobbyGame.HostAddress,
+			HostNickname: lobbyGame.HostNickname,
+			PlayerCount:  lobbyGame.PlayerCount,
+			MinPlayers:   lobbyGame.MinPlayers,
+			Capacity:     lobbyGame.Capacity,
+			CreatedAt:    lobbyGame.CreatedAt,
+		}
+	}
+
+	return &game.Lobby{
+		Version: r.Version,
+		Games:   games,
+	}
+}
+
+type lobbyGameResponse struct {
+	HostAddress  string    `json:"hostAddress"`
+	HostNickname string    `json:"hostNickname"`
+	PlayerCount  int       `json:"playerCount"`
+	MinPlayers   int       `json:"minPlayers"`
+	Capacity     int       `json:"capacity"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type phaseExecutionResponse struct {
+	HostAddress      string   `json:"hostAddress"`
+	PhaseOutcome     int      `json:"phaseOutcome"`
+	CurrentPhase     int      `json:"currentPhase"`
+	KilledPlayers    []string `json:"killedPlayers"`
+	ConvictedPlayers []string `json:"convictedPlayers"`
+	ForfeitedPlayers []string `json:"forfeitedPlayers"`
+}
+
+func (r *phaseExecutionResponse) toPhaseExecution() *game.PhaseExecution {
+	return &game.PhaseExecution{
+		HostAddress:      r.HostAddress,
+		PhaseOutcome:     game.PhaseOutcome(r.PhaseOutcome),
+		CurrentPhase:     game.TimeOfDay(r.CurrentPhase),
+		KilledPlayers:    r.KilledPlayers,
+		ConvictedPlayers: r.ConvictedPlayers,
+		ForfeitedPlayers: r.ForfeitedPlayers,
+	}
+}
+
+type playerResponse struct {
+	PlayerAddress  string `json:"playerAddress"`
+	PlayerNickname string `json:"playerNickname"`
+	PlayerRole     *int   `json:"playerRole"`
+	Dead           *bool  `json:"dead"`
+	Convicted      *bool  `json:"convicted"`
+	Forfeited      *bool  `json:"forfeited"`
+}
+
+func (r *playerResponse) toPlayer() *game.Player {
+	player := &game.Player{
+		PlayerAddress:  r.PlayerAddress,
+		PlayerNickname: r.PlayerNickname,
+	}
+
+	if r.PlayerRole != nil {
+		player.PlayerRole = game.PlayerRole(*r.PlayerRole)
+	}
+
+	if r.Dead != nil {
+		player.Dead = *r.Dead
+	}
+
+	if r.Convicted != nil {
+		player.Convicted = *r.Convicted
+	}
+
+	if r.Forfeited != nil {
+		player.Forfeited = *r.Forfeited
+	}
+
+	return player
+}
+
+type playerStatsResponse struct {
+	PlayerAddress      string `json:"playerAddress"`
+	GamesPlayed        int    `json:"gamesPlayed"`
+	CivilianWins       int    `json:"civilianWins"`
+	MafiaWins          int    `json:"mafiaWins"`
+	TimesConvicted     int    `json:"timesConvicted"`
+	TimesKilled        int    `json:"timesKilled"`
+	Accusations        int    `json:"accusations"`
+	CorrectAccusations int    `json:"correctAccusations"`
+}
+
+type spectatorResponse struct {
+	SpectatorAddress string `json:"spectatorAddress"`
+	GodView          bool   `json:"godView"`
+}
+
+type votesResponse struct {
+	MafiaAccusations map[string]string `json:"mafiaAccusations"`
+	KillVotes        map[string]string `json:"killVotes"`
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jrh3k5/mafia-dapp-http/client"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+var timesOfDay = map[game.TimeOfDay]string{game.TimeOfDayDay: "day", game.TimeOfDayNight: "night"}
+var phaseOutcomes = map[game.PhaseOutcome]string{game.PhaseOutcomeContinuation: "continuation", game.PhaseOutcomeCivilianVictory: "civilian victory", game.PhaseOutcomeMafiaVictory: "Mafia victory"}
+var playerRoles = map[game.PlayerRole]string{game.PlayerRoleCivilian: "civilian", game.PlayerRoleMafia: "Mafia"}
+
+type commandLine struct {
+	client        *client.Client
+	hostAddress   string
+	actingAddress string
+	jsonOutput    bool
+	stdout        io.Writer
+	stderr        io.Writer
+}
+
+func (c *commandLine) initialize(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	configPath := flags.String("config", "", "path to a JSON game configuration document")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var config *game.GameConfig
+	if *configPath != "" {
+		configBytes, err := os.ReadFile(*configPath)
+		if err != nil {
+			return fmt.Errorf("failed to read game configuration: %w", err)
+		}
+
+		config, err = client.ParseGameConfig(configBytes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := c.client.InitializeGame(ctx, c.hostAddress, config); err != nil {
+		return err
+	}
+
+	return c.printStatus(fmt.Sprintf("Initialized game hosted by '%s'", c.hostAddress))
+}
+
+func (c *commandLine) join(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: join <nickname>")
+	}
+
+	if err := c.requireActingAddress(); err != nil {
+		return err
+	}
+
+	if err := c.client.JoinGame(ctx, c.hostAddress, c.actingAddress, args[0]); err != nil {
+		return err
+	}
+
+	return c.printStatus(fmt.Sprintf("'%s' joined game hosted by '%s' as '%s'", c.actingAddress, c.hostAddress, args[0]))
+}
+
+func (c *commandLine) start(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: start")
+	}
+
+	if err := c.client.StartGame(ctx, c.hostAddress); err != nil {
+		return err
+	}
+
+	return c.printStatus(fmt.Sprintf("Started game hosted by '%s'", c.hostAddress))
+}
+
+func (c *commandLine) vote(ctx context.Context, action string, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: %s <playerAddress>", action)
+	}
+
+	if err := c.requireActingAddress(); err != nil {
+		return err
+	}
+
+	switch action {
+	case "accuse":
+		if err := c.client.AccuseAsMafia(ctx, c.hostAddress, c.actingAddress, args[0]); err != nil {
+			return err
+		}
+
+		return c.printStatus(fmt.Sprintf("'%s' accused '%s' of being in the Mafia", c.actingAddress, args[0]))
+	default:
+		if err := c.client.VoteToKill(ctx, c.hostAddress, c.actingAddress, args[0]); err != nil {
+			return err
+		}
+
+		return c.printStatus(fmt.Sprintf("'%s' voted to kill '%s'", c.actingAddress, args[0]))
+	}
+}
+
+func (c *commandLine) execute(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: execute")
+	}
+
+	phaseExecution, err := c.client.ExecutePhase(ctx, c.hostAddress)
+	if err != nil {
+		return err
+	}
+
+	return c.printPhaseExecution(phaseExecution)
+}
+
+func (c *commandLine) wait(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: wait start|phase")
+	}
+
+	switch args[0] {
+	case "start":
+		if err := c.client.WaitForGameStart(ctx, c.hostAddress); err != nil {
+			return err
+		}
+
+		return c.printStatus(fmt.Sprintf("Game hosted by '%s' has started", c.hostAddress))
+	case "phase":
+		phaseExecution, err := c.client.WaitForPhaseExecution(ctx, c.hostAddress)
+		if err != nil {
+			return err
+		}
+
+		return c.printPhaseExecution(phaseExecution)
+	default:
+		return fmt.Errorf("cannot wait for '%s'; expected start or phase", args[0])
+	}
+}
+
+func (c *commandLine) players(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: players")
+	}
+
+	var players []*client.ListedPlayer
+	if c.actingAddress != "" {
+		player, err := c.client.GetPlayer(ctx, c.hostAddress, c.actingAddress)
+		if err != nil {
+			return err
+		} else if player == nil {
+			return fmt.Errorf("'%s' is not a member of the game hosted by '%s'", c.actingAddress, c.hostAddress)
+		}
+
+		// players always see their own role
+		players = append(players, &client.ListedPlayer{
+			Player:       player,
+			RoleRevealed: true,
+		})
+	} else {
+		listedPlayers, err := c.client.ListPlayers(ctx, c.hostAddress)
+		if err != nil {
+			return err
+		}
+		players = listedPlayers
+	}
+
+	if c.jsonOutput {
+		playerOutputs := make([]*playerOutput, len(players))
+		for playerIndex, player := range players {
+			playerOutputs[playerIndex] = toPlayerOutput(player)
+		}
+
+		if c.actingAddress != "" {
+			return c.printJSON(playerOutputs[0])
+		}
+		return c.printJSON(playerOutputs)
+	}
+
+	writer := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "ADDRESS\tNICKNAME\tSTATUS\tROLE")
+	for _, player := range players {
+		status := "alive"
+		if player.Convicted {
+			status = "convicted"
+		} else if player.Dead {
+			status = "dead"
+		}
+
+		role := "unknown"
+		if player.RoleRevealed {
+			role = playerRoles[player.PlayerRole]
+		}
+
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", player.PlayerAddress, player.PlayerNickname, status, role)
+	}
+	return writer.Flush()
+}
+
+func (c *commandLine) cancel(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: cancel")
+	}
+
+	if err := c.client.CancelGame(ctx, c.hostAddress); err != nil {
+		return err
+	}
+
+	return c.printStatus(fmt.Sprintf("Cancelled game hosted by '%s'", c.hostAddress))
+}
+
+func (c *commandLine) printJSON(output any) error {
+	return json.NewEncoder(c.stdout).Encode(output)
+}
+
+func (c *commandLine) printPhaseExecution(phaseExecution *game.PhaseExecution) error {
+	if c.jsonOutput {
+		return c.printJSON(&phaseExecutionOutput{
+			HostAddress:      phaseExecution.HostAddress,
+			PhaseOutcome:     int(phaseExecution.PhaseOutcome),
+			CurrentPhase:     int(phaseExecution.CurrentPhase),
+			KilledPlayers:    nonNilAddresses(phaseExecution.KilledPlayers),
+			ConvictedPlayers: nonNilAddresses(phaseExecution.ConvictedPlayers),
+			ForfeitedPlayers: phaseExecution.ForfeitedPlayers,
+		})
+	}
+
+	fmt.Fprintf(c.stdout, "The %s has ended\n", timesOfDay[phaseExecution.CurrentPhase])
+	printAddresses(c.stdout, "Convicted", phaseExecution.ConvictedPlayers)
+	printAddresses(c.stdout, "Killed", phaseExecution.KilledPlayers)
+	printAddresses(c.stdout, "Forfeited", phaseExecution.ForfeitedPlayers)
+	_, err := fmt.Fprintf(c.stdout, "Outcome: %s\n", phaseOutcomes[phaseExecution.PhaseOutcome])
+	return err
+}
+
+// printStatus reports the success of a command that has no response body
+func (c *commandLine) printStatus(message string) error {
+	if c.jsonOutput {
+		return c.printJSON(&statusOutput{
+			Success: true,
+			Message: message,
+		})
+	}
+
+	_, err := fmt.Fprintln(c.stdout, message)
+	return err
+}
+
+func (c *commandLine) requireActingAddress() error {
+	if c.actingAddress == "" {
+		return errors.New("the player on whose behalf to act must be given with --as")
+	}
+	return nil
+}
+
+func printAddresses(writer io.Writer, label string, addresses []string) {
+	if len(addresses) > 0 {
+		fmt.Fprintf(writer, "%s: %s\n", label, strings.Join(addresses, ", "))
+	}
+}
+
+// nonNilAddresses keeps empty lists of addresses printed as empty JSON arrays rather than null
+func nonNilAddresses(addresses []string) []string {
+	if addresses == nil {
+		return []string{}
+	}
+	return addresses
+}
+
+type statusOutput struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// playerOutput prints a player as JSON with the same fields as the server, leaving out a role that has not been revealed
+type playerOutput struct {
+	PlayerAddress  string `json:"playerAddress"`
+	PlayerNickname string `json:"playerNickname"`
+	PlayerRole     *int   `json:"playerRole,omitempty"`
+	Dead           bool   `json:"dead"`
+	Convicted      bool   `json:"convicted"`
+	Forfeited      bool   `json:"forfeited"`
+}
+
+func toPlayerOutput(player *client.ListedPlayer) *playerOutput {
+	output := &playerOutput{
+		PlayerAddress:  player.PlayerAddress,
+		PlayerNickname: player.PlayerNickname,
+		Dead:           player.Dead,
+		Convicted:      player.Convicted,
+		Forfeited:      player.Forfeited,
+	}
+
+	if player.RoleRevealed {
+		playerRole := int(player.PlayerRole)
+		output.PlayerRole = &playerRole
+	}
+
+	return output
+}
+
+// phaseExecutionOutput prints a phase execution as JSON with the same fields as the server
+type phaseExecutionOutput struct {
+	HostAddress      string   `json:"hostAddress"`
+	PhaseOutcome     int      `json:"phaseOutcome"`
+	CurrentPhase     int      `json:"currentPhase"`
+	KilledPlayers    []string `json:"killedPlayers"`
+	ConvictedPlayers []string `json:"convictedPlayers"`
+	ForfeitedPlayers []string `json:"forfeitedPlayers,omitempty"`
+}
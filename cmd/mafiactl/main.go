@@ -0,0 +1,113 @@
+// mafiactl drives games on a running mock server from the command line.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jrh3k5/mafia-dapp-http/client"
+)
+
+const usage = `Usage: mafiactl [flags] <command> [arguments]
+
+Commands:
+  init [--config <file>]     initialize a game, optionally configured by a JSON document
+  join <nickname>            join the game as the player given by --as
+  start                      start the game
+  accuse <playerAddress>     accuse a player of being in the Mafia as the player given by --as
+  kill <playerAddress>       vote to kill a player as the Mafia member given by --as
+  execute                    execute the current phase
+  wait start|phase           wait for the game to start or for the current phase to be executed
+  players                    list the players; with --as, show that player's own details
+  cancel                     cancel the game
+
+Flags:
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := flag.NewFlagSet("mafiactl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	serverURL := flags.String("server", envOrDefault("MAFIACTL_SERVER", "http://localhost:3000"), "base URL of the mock server (env MAFIACTL_SERVER)")
+	hostAddress := flags.String("host", os.Getenv("MAFIACTL_HOST"), "address of the host of the game; defaults to the address given by --as (env MAFIACTL_HOST)")
+	actingAddress := flags.String("as", os.Getenv("MAFIACTL_AS"), "address of the player on whose behalf to act (env MAFIACTL_AS)")
+	output := flags.String("output", "text", "output format: text or json")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format: '%s'", *output)
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("a command must be supplied")
+	}
+
+	if *hostAddress == "" {
+		*hostAddress = *actingAddress
+	}
+
+	if *hostAddress == "" {
+		return errors.New("the game must be identified with --host or --as")
+	}
+
+	cli := &commandLine{
+		client:        client.New(*serverURL, nil),
+		hostAddress:   *hostAddress,
+		actingAddress: *actingAddress,
+		jsonOutput:    *output == "json",
+		stdout:        stdout,
+		stderr:        stderr,
+	}
+
+	ctx := context.Background()
+	command, commandArgs := flags.Arg(0), flags.Args()[1:]
+	switch command {
+	case "init":
+		return cli.initialize(ctx, commandArgs)
+	case "join":
+		return cli.join(ctx, commandArgs)
+	case "start":
+		return cli.start(ctx, commandArgs)
+	case "accuse":
+		return cli.vote(ctx, "accuse", commandArgs)
+	case "kill":
+		return cli.vote(ctx, "kill", commandArgs)
+	case "execute":
+		return cli.execute(ctx, commandArgs)
+	case "wait":
+		return cli.wait(ctx, commandArgs)
+	case "players":
+		return cli.players(ctx, commandArgs)
+	case "cancel":
+		return cli.cancel(ctx, commandArgs)
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown command: '%s'", command)
+	}
+}
+
+func envOrDefault(name string, defaultValue string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("mafiactl", func() {
+	// recordedRequest is a request received by the stub server
+	type recordedRequest struct {
+		method string
+		path   string
+		query  map[string][]string
+		body   string
+	}
+
+	var stubServer *httptest.Server
+	var requests []*recordedRequest
+	var responseStatus int
+	var responseBody string
+	var stdout *bytes.Buffer
+	var stderr *bytes.Buffer
+
+	BeforeEach(func() {
+		// keep the environment of whoever runs the tests from choosing the game
+		GinkgoT().Setenv("MAFIACTL_SERVER", "")
+		GinkgoT().Setenv("MAFIACTL_HOST", "")
+		GinkgoT().Setenv("MAFIACTL_AS", "")
+
+		requests = nil
+		responseStatus = http.StatusOK
+		responseBody = ""
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+
+		stubServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests = append(requests, &recordedRequest{
+				method: r.Method,
+				path:   r.URL.EscapedPath(),
+				query:  r.URL.Query(),
+				body:   string(body),
+			})
+
+			if responseBody != "" {
+				w.Header().Set("Content-Type", "application/json")
+			}
+			w.WriteHeader(responseStatus)
+			_, _ = w.Write([]byte(responseBody))
+		}))
+		DeferCleanup(stubServer.Close)
+	})
+
+	// runAgainstStub runs mafiactl with the given arguments against the stub server
+	runAgainstStub := func(args ...string) error {
+		return run(append([]string{"--server", stubServer.URL + "/"}, args...), stdout, stderr)
+	}
+
+	Context("parsing the command line", func() {
+		DescribeTable("rejects malformed command lines without contacting the server",
+			func(expectedError string, args ...string) {
+				Expect(runAgainstStub(args...)).To(MatchError(expectedError), "the command line should be rejected")
+				Expect(requests).To(BeEmpty(), "no request should be sent to the server")
+			},
+			Entry("no command", "a command must be supplied", "--host", "0xhost"),
+			Entry("unknown command", "unknown command: 'dance'", "--host", "0xhost", "dance"),
+			Entry("unknown output format", "unknown output format: 'yaml'", "--host", "0xhost", "--output", "yaml", "start"),
+			Entry("no game", "the game must be identified with --host or --as", "start"),
+			Entry("join without a nickname", "usage: join <nickname>", "--as", "0xplayer", "join"),
+			Entry("join without a player", "the player on whose behalf to act must be given with --as", "--host", "0xhost", "join", "Alice"),
+			Entry("accuse without a target", "usage: accuse <playerAddress>", "--as", "0xplayer", "accuse"),
+			Entry("kill without a player", "the player on whose behalf to act must be given with --as", "--host", "0xhost", "kill", "0xtarget"),
+			Entry("execute with arguments", "usage: execute", "--host", "0xhost", "execute", "now"),
+			Entry("wait without a target", "usage: wait start|phase", "--host", "0xhost", "wait"),
+			Entry("wait for something unknown", "cannot wait for 'lunch'; expected start or phase", "--host", "0xhost", "wait", "lunch"),
+			Entry("players with arguments", "usage: players", "--host", "0xhost", "players", "all"),
+			Entry("cancel with arguments", "usage: cancel", "--host", "0xhost", "cancel", "now"),
+		)
+
+		It("prints the usage when no command is given", func() {
+			Expect(runAgainstStub("--host", "0xhost")).ToNot(Succeed(), "running without a command should fail")
+			Expect(stderr.String()).To(ContainSubstring("Usage: mafiactl"), "the usage should be printed")
+		})
+
+		DescribeTable("sends each command to the server",
+			func(args []string, expectedMethod string, expectedPath string, expectedQuery map[string][]string) {
+				Expect(runAgainstStub(args...)).To(Succeed(), "running the command should not fail")
+				Expect(requests).To(HaveLen(1), "exactly one request should be sent")
+				Expect(requests[0].method).To(Equal(expectedMethod), "the request should use the expected method")
+				Expect(requests[0].path).To(Equal(expectedPath), "the request should be sent to the expected path")
+				if expectedQuery == nil {
+					Expect(requests[0].query).To(BeEmpty(), "the request should have no query parameters")
+				} else {
+					Expect(requests[0].query).To(Equal(expectedQuery), "the request should have the expected query parameters")
+				}
+			},
+			Entry("init", []string{"--host", "0xhost", "init"}, http.MethodPost, "/game/0xhost", nil),
+			Entry("join", []string{"--host", "0xhost", "--as", "0xplayer", "join", "Alice"}, http.MethodPost, "/game/0xhost/join", map[string][]string{
+				"playerAddress":  {"0xplayer"},
+				"playerNickname": {"Alice"},
+			}),
+			Entry("join hosting the game with --as alone", []string{"--as", "0xhost", "join", "Hostess"}, http.MethodPost, "/game/0xhost/join", map[string][]string{
+				"playerAddress":  {"0xhost"},
+				"playerNickname": {"Hostess"},
+			}),
+			Entry("start", []string{"--host", "0xhost", "start"}, http.MethodPost, "/game/0xhost/start", nil),
+			Entry("accuse", []string{"--host", "0xhost", "--as", "0xplayer", "accuse", "0xtarget"}, http.MethodPost, "/game/0xhost/players/0xplayer/vote/accuse", map[string][]string{
+				"playerAddress": {"0xtarget"},
+			}),
+			Entry("kill", []string{"--host", "0xhost", "--as", "0xmafia", "kill", "0xtarget"}, http.MethodPost, "/game/0xhost/players/0xmafia/vote/kill", map[string][]string{
+				"playerAddress": {"0xtarget"},
+			}),
+			Entry("wait start", []string{"--host", "0xhost", "wait", "start"}, http.MethodGet, "/game/0xhost/start/wait", nil),
+			Entry("cancel", []string{"--host", "0xhost", "cancel"}, http.MethodDelete, "/game/0xhost", nil),
+			Entry("an address that must be escaped", []string{"--host", "0x host/1", "start"}, http.MethodPost, "/game/0x%20host%2F1/start", nil),
+		)
+
+		It("reads the game configuration for init from the given file", func() {
+			configPath := filepath.Join(GinkgoT().TempDir(), "game.json")
+			Expect(os.WriteFile(configPath, []byte(`{"mafiaCount":2}`), 0o600)).To(Succeed(), "writing the game configuration should not fail")
+
+			Expect(runAgainstStub("--host", "0xhost", "init", "--config", configPath)).To(Succeed(), "initializing the game should not fail")
+			Expect(requests).To(HaveLen(1), "exactly one request should be sent")
+			var sentConfig map[string]any
+			Expect(json.Unmarshal([]byte(requests[0].body), &sentConfig)).To(Succeed(), "the game configuration should be sent as JSON")
+			Expect(sentConfig).To(HaveKeyWithValue("mafiaCount", BeNumerically("==", 2)), "the configured settings should be sent")
+			Expect(sentConfig).To(HaveKeyWithValue("mafiaRatio", BeNumerically("==", 5)), "the settings not configured should be sent with their defaults")
+		})
+
+		It("rejects a malformed game configuration without contacting the server", func() {
+			configPath := filepath.Join(GinkgoT().TempDir(), "game.json")
+			Expect(os.WriteFile(configPath, []byte(`{"mafiaCont":2}`), 0o600)).To(Succeed(), "writing the game configuration should not fail")
+
+			Expect(runAgainstStub("--host", "0xhost", "init", "--config", configPath)).To(MatchError(ContainSubstring("invalid game configuration document")), "the misspelled setting should be rejected")
+			Expect(requests).To(BeEmpty(), "no request should be sent to the server")
+		})
+
+		It("reads the server and game from the environment", func() {
+			GinkgoT().Setenv("MAFIACTL_SERVER", stubServer.URL)
+			GinkgoT().Setenv("MAFIACTL_AS", "0xenv")
+
+			Expect(run([]string{"start"}, stdout, stderr)).To(Succeed(), "starting the game should not fail")
+			Expect(requests).To(HaveLen(1), "exactly one request should be sent")
+			Expect(requests[0].path).To(Equal("/game/0xenv/start"), "the game should be identified from the environment")
+		})
+
+		It("reports the server's error", func() {
+			responseStatus = http.StatusConflict
+			responseBody = `{"code":"conflict","message":"the game has already started"}`
+
+			err := runAgainstStub("--host", "0xhost", "start")
+			Expect(err).To(HaveOccurred(), "the server's error should fail the command")
+			Expect(err.Error()).To(ContainSubstring("server responded with status 409"), "the error should carry the status")
+			Expect(err.Error()).To(ContainSubstring("the game has already started"), "the error should carry the server's message")
+			Expect(stdout.String()).To(BeEmpty(), "nothing should be printed on failure")
+		})
+	})
+
+	Context("formatting the output", func() {
+		const phaseExecutionBody = `{"hostAddress":"0xhost","phaseOutcome":1,"currentPhase":0,"killedPlayers":[],"convictedPlayers":["0xmafia"],"forfeitedPlayers":["0xquitter","0xother"]}`
+		const playersBody = `[{"playerAddress":"0xalice","playerNickname":"Alice","dead":false,"convicted":false},{"playerAddress":"0xbob","playerNickname":"Bob","dead":true,"convicted":false},{"playerAddress":"0xcarol","playerNickname":"Carol","dead":false,"convicted":true}]`
+
+		It("prints statuses as text", func() {
+			Expect(runAgainstStub("--host", "0xhost", "--as", "0xplayer", "accuse", "0xtarget")).To(Succeed(), "accusing should not fail")
+			Expect(stdout.String()).To(Equal("'0xplayer' accused '0xtarget' of being in the Mafia\n"), "the accusation should be described")
+		})
+
+		It("prints statuses as JSON", func() {
+			Expect(runAgainstStub("--host", "0xhost", "--output", "json", "start")).To(Succeed(), "starting should not fail")
+
+			var status statusOutput
+			Expect(json.Unmarshal(stdout.Bytes(), &status)).To(Succeed(), "the output should be JSON")
+			Expect(status.Success).To(BeTrue(), "the status should report success")
+			Expect(status.Message).To(Equal("Started game hosted by '0xhost'"), "the status should describe the command")
+		})
+
+		DescribeTable("prints phase executions as text",
+			func(args ...string) {
+				responseBody = phaseExecutionBody
+
+				Expect(runAgainstStub(append([]string{"--host", "0xhost"}, args...)...)).To(Succeed(), "the command should not fail")
+				Expect(stdout.String()).To(Equal("The day has ended\nConvicted: 0xmafia\nForfeited: 0xquitter, 0xother\nOutcome: civilian victory\n"), "the phase execution should be described, omitting empty lists")
+			},
+			Entry("execute", "execute"),
+			Entry("wait phase", "wait", "phase"),
+		)
+
+		DescribeTable("prints phase executions as JSON",
+			func(args ...string) {
+				responseBody = phaseExecutionBody + "\n"
+
+				Expect(runAgainstStub(append([]string{"--host", "0xhost", "--output", "json"}, args...)...)).To(Succeed(), "the command should not fail")
+				Expect(stdout.String()).To(MatchJSON(phaseExecutionBody), "the phase execution should be printed with the same fields as the server returned")
+			},
+			Entry("execute", "execute"),
+			Entry("wait phase", "wait", "phase"),
+		)
+
+		It("prints the players as a table", func() {
+			responseBody = playersBody
+
+			Expect(runAgainstStub("--host", "0xhost", "players")).To(Succeed(), "listing the players should not fail")
+			Expect(requests).To(HaveLen(1), "the players should be listed without looking up each of them")
+			Expect(requests[0].path).To(Equal("/game/0xhost/players"), "the players should be listed")
+			Expect(stdout.String()).To(Equal(""+
+				"ADDRESS  NICKNAME  STATUS     ROLE\n"+
+				"0xalice  Alice     alive      unknown\n"+
+				"0xbob    Bob       dead       unknown\n"+
+				"0xcarol  Carol     convicted  unknown\n"), "each player should be listed with their status")
+		})
+
+		It("prints a player's own details as a table", func() {
+			responseBody = `{"playerAddress":"0xalice","playerNickname":"Alice","playerRole":1,"dead":false,"convicted":false}`
+
+			Expect(runAgainstStub("--host", "0xhost", "--as", "0xalice", "players")).To(Succeed(), "showing the player should not fail")
+			Expect(requests).To(HaveLen(1), "exactly one request should be sent")
+			Expect(requests[0].path).To(Equal("/game/0xhost/players/0xalice"), "the player should be looked up")
+			Expect(stdout.String()).To(Equal(""+
+				"ADDRESS  NICKNAME  STATUS  ROLE\n"+
+				"0xalice  Alice     alive   Mafia\n"), "the player should be listed with their role")
+		})
+
+		It("prints the players as JSON", func() {
+			responseBody = playersBody
+
+			Expect(runAgainstStub("--host", "0xhost", "--output", "json", "players")).To(Succeed(), "listing the players should not fail")
+			Expect(stdout.String()).To(MatchJSON(`[
+				{"playerAddress":"0xalice","playerNickname":"Alice","dead":false,"convicted":false,"forfeited":false},
+				{"playerAddress":"0xbob","playerNickname":"Bob","dead":true,"convicted":false,"forfeited":false},
+				{"playerAddress":"0xcarol","playerNickname":"Carol","dead":false,"convicted":true,"forfeited":false}
+			]`), "the players should be printed with the same fields as the server returned, leaving out the roles that are hidden")
+		})
+	})
+})
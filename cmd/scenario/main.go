@@ -0,0 +1,92 @@
+// scenario plays scripted game scenarios and reports where the results differed from what the scenarios expected.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jrh3k5/mafia-dapp-http/client"
+	"github.com/jrh3k5/mafia-dapp-http/config"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+	"github.com/jrh3k5/mafia-dapp-http/logging"
+	"github.com/jrh3k5/mafia-dapp-http/scenario"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := flag.NewFlagSet("scenario", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: scenario [flags] <scenario file>...")
+		flags.PrintDefaults()
+	}
+	serverURL := flags.String("server", "", "base URL of the server against which to play the scenarios; if blank, they are played in-process")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("at least one scenario file must be supplied")
+	}
+
+	var failedCount int
+	for _, path := range flags.Args() {
+		var driver scenario.Driver
+		if *serverURL != "" {
+			driver = client.New(*serverURL, nil)
+		} else {
+			driver = game.NewInMemoryGameEngine(logging.NewLogger(stderr, config.LogFormatText, config.LogLevelWarn))
+		}
+
+		if !runScenario(context.Background(), driver, path, stdout) {
+			failedCount++
+		}
+	}
+
+	if failedCount > 0 {
+		return fmt.Errorf("%d of %d scenarios failed", failedCount, flags.NArg())
+	}
+
+	return nil
+}
+
+// runScenario plays the scenario in the given file, reporting its results; this returns false if the scenario failed
+func runScenario(ctx context.Context, driver scenario.Driver, path string, stdout io.Writer) bool {
+	loadedScenario, err := scenario.Load(path)
+	if err != nil {
+		fmt.Fprintf(stdout, "FAIL %s\n    %v\n", path, err)
+		return false
+	}
+
+	name := path
+	if loadedScenario.Name != "" {
+		name = fmt.Sprintf("%s (%s)", path, loadedScenario.Name)
+	}
+
+	mismatches, err := scenario.Run(ctx, driver, loadedScenario)
+	if err != nil || len(mismatches) > 0 {
+		fmt.Fprintf(stdout, "FAIL %s\n", name)
+		for _, mismatch := range mismatches {
+			fmt.Fprintf(stdout, "    %s\n", mismatch)
+		}
+		if err != nil {
+			fmt.Fprintf(stdout, "    %v\n", err)
+		}
+		return false
+	}
+
+	fmt.Fprintf(stdout, "PASS %s\n", name)
+	return true
+}
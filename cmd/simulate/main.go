@@ -0,0 +1,106 @@
+// simulate plays many games in-process with bots to evaluate the balance of the game rules.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jrh3k5/mafia-dapp-http/bots"
+	"github.com/jrh3k5/mafia-dapp-http/config"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+	"github.com/jrh3k5/mafia-dapp-http/logging"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) error {
+	defaultConfig := game.DefaultGameConfig()
+
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	gameCount := flags.Int("games", 1000, "number of games to simulate")
+	playerCount := flags.Int("players", 8, "number of players in each game")
+	mafiaCount := flags.Int("mafia-count", 0, "exact number of Mafia members in each game; if 0, --mafia-ratio is used")
+	mafiaRatio := flags.Int("mafia-ratio", defaultConfig.MafiaRatio, "number of players per Mafia member, rounded up")
+	tiePolicy := flags.String("tie-policy", string(defaultConfig.TiePolicy), "how tied votes are resolved: none, random, or all")
+	startingPhase := flags.Int("starting-phase", int(defaultConfig.StartingPhase), "0 to start each game during the day, 1 to start it at night")
+	civilianStrategyName := flags.String("civilian-strategy", "random", fmt.Sprintf("strategy used by civilian bots: %s", strings.Join(bots.StrategyNames, ", ")))
+	mafiaStrategyName := flags.String("mafia-strategy", "random", fmt.Sprintf("strategy used by Mafia bots: %s", strings.Join(bots.StrategyNames, ", ")))
+	maxRounds := flags.Int("max-rounds", 100, "number of rounds after which a game is abandoned as unfinished")
+	seed := flags.Int64("seed", 0, "seed for random choices; if 0, the current time is used")
+	verbose := flags.Bool("verbose", false, "log the progress of each game played by the game engine")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *gameCount <= 0 {
+		return fmt.Errorf("the number of games must be positive, not %d", *gameCount)
+	} else if *maxRounds <= 0 {
+		return fmt.Errorf("the maximum number of rounds must be positive, not %d", *maxRounds)
+	}
+
+	civilianStrategy, err := bots.NewStrategy(*civilianStrategyName)
+	if err != nil {
+		return err
+	}
+
+	mafiaStrategy, err := bots.NewStrategy(*mafiaStrategyName)
+	if err != nil {
+		return err
+	}
+
+	gameConfig := game.DefaultGameConfig()
+	gameConfig.MinPlayers = *playerCount
+	gameConfig.MafiaCount = *mafiaCount
+	gameConfig.MafiaRatio = *mafiaRatio
+	gameConfig.TiePolicy = game.TiePolicy(*tiePolicy)
+	gameConfig.StartingPhase = game.TimeOfDay(*startingPhase)
+	if err := gameConfig.Validate(); err != nil {
+		return fmt.Errorf("invalid game configuration: %w", err)
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	rand.Seed(*seed)
+
+	// the game engine logs its progress, which would bury the report unless asked for
+	logLevel := config.LogLevelWarn
+	if *verbose {
+		logLevel = config.LogLevelInfo
+	}
+
+	simulator := &simulator{
+		config:           gameConfig,
+		playerCount:      *playerCount,
+		civilianStrategy: civilianStrategy,
+		mafiaStrategy:    mafiaStrategy,
+		maxPhases:        *maxRounds * 2,
+		logger:           logging.NewLogger(stderr, config.LogFormatText, logLevel),
+	}
+
+	report := &report{}
+	for gameIndex := 0; gameIndex < *gameCount; gameIndex++ {
+		result, err := simulator.simulate(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to simulate game %d: %w", gameIndex+1, err)
+		}
+		report.add(result)
+	}
+
+	fmt.Fprintf(stdout, "Seed: %d\n", *seed)
+	report.print(stdout)
+	return nil
+}
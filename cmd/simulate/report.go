@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// report aggregates the results of simulated games
+type report struct {
+	games         int
+	civilianWins  int
+	mafiaWins     int
+	unfinished    int
+	phases        int
+	stalledPhases int
+	// finishedPhases is the number of phases executed across all finished games
+	finishedPhases int
+	mafiaCounts    map[int]int
+}
+
+func (r *report) add(result *gameResult) {
+	r.games++
+	r.phases += result.phases
+	r.stalledPhases += result.stalledPhases
+
+	if r.mafiaCounts == nil {
+		r.mafiaCounts = make(map[int]int)
+	}
+	r.mafiaCounts[result.mafiaCount]++
+
+	if !result.finished {
+		r.unfinished++
+		return
+	}
+
+	r.finishedPhases += result.phases
+	switch result.outcome {
+	case game.PhaseOutcomeCivilianVictory:
+		r.civilianWins++
+	case game.PhaseOutcomeMafiaVictory:
+		r.mafiaWins++
+	}
+}
+
+func (r *report) print(writer io.Writer) {
+	tabWriter := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+	defer tabWriter.Flush()
+
+	fmt.Fprintf(tabWriter, "Games simulated:\t%d\n", r.games)
+
+	mafiaCounts := make([]int, 0, len(r.mafiaCounts))
+	for mafiaCount := range r.mafiaCounts {
+		mafiaCounts = append(mafiaCounts, mafiaCount)
+	}
+	sort.Ints(mafiaCounts)
+	for _, mafiaCount := range mafiaCounts {
+		fmt.Fprintf(tabWriter, "Games with %d Mafia members:\t%d\n", mafiaCount, r.mafiaCounts[mafiaCount])
+	}
+
+	fmt.Fprintf(tabWriter, "Civilian wins:\t%d\t(%s)\n", r.civilianWins, percentage(r.civilianWins, r.games))
+	fmt.Fprintf(tabWriter, "Mafia wins:\t%d\t(%s)\n", r.mafiaWins, percentage(r.mafiaWins, r.games))
+	fmt.Fprintf(tabWriter, "Unfinished games:\t%d\t(%s)\n", r.unfinished, percentage(r.unfinished, r.games))
+
+	// a round is a day and a night
+	if finishedGames := r.games - r.unfinished; finishedGames > 0 {
+		fmt.Fprintf(tabWriter, "Average rounds per finished game:\t%.2f\n", float64(r.finishedPhases)/float64(finishedGames)/2)
+	}
+
+	fmt.Fprintf(tabWriter, "Phases stalled by ties:\t%d\t(%s of %d phases)\n", r.stalledPhases, percentage(r.stalledPhases, r.phases), r.phases)
+}
+
+func percentage(count int, total int) string {
+	if total == 0 {
+		return "n/a"
+	}
+
+	return fmt.Sprintf("%.1f%%", 100*float64(count)/float64(total))
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+var _ = Describe("report", func() {
+	It("aggregates the results of games", func() {
+		gameReport := &report{}
+		gameReport.add(&gameResult{outcome: game.PhaseOutcomeCivilianVictory, finished: true, mafiaCount: 1, phases: 4, stalledPhases: 1})
+		gameReport.add(&gameResult{outcome: game.PhaseOutcomeCivilianVictory, finished: true, mafiaCount: 2, phases: 6})
+		gameReport.add(&gameResult{outcome: game.PhaseOutcomeMafiaVictory, finished: true, mafiaCount: 2, phases: 2, stalledPhases: 2})
+		gameReport.add(&gameResult{mafiaCount: 1, phases: 200, stalledPhases: 150})
+
+		Expect(gameReport.games).To(Equal(4), "every game should be counted")
+		Expect(gameReport.civilianWins).To(Equal(2), "the civilian wins should be counted")
+		Expect(gameReport.mafiaWins).To(Equal(1), "the Mafia wins should be counted")
+		Expect(gameReport.unfinished).To(Equal(1), "the abandoned game should be counted as unfinished")
+		Expect(gameReport.phases).To(Equal(212), "the phases of every game should be counted")
+		Expect(gameReport.finishedPhases).To(Equal(12), "only the phases of finished games should count towards their length")
+		Expect(gameReport.stalledPhases).To(Equal(153), "the stalled phases of every game should be counted")
+		Expect(gameReport.mafiaCounts).To(Equal(map[int]int{1: 2, 2: 2}), "the games should be counted by their number of Mafia members")
+
+		output := &bytes.Buffer{}
+		gameReport.print(output)
+		Expect(output.String()).To(MatchRegexp(`Games simulated:\s+4\n`), "the number of games should be reported")
+		Expect(output.String()).To(MatchRegexp(`Games with 1 Mafia members:\s+2\nGames with 2 Mafia members:\s+2\n`), "the games should be reported by their number of Mafia members, fewest first")
+		Expect(output.String()).To(MatchRegexp(`Civilian wins:\s+2\s+\(50\.0%\)`), "the civilian win rate should be reported")
+		Expect(output.String()).To(MatchRegexp(`Mafia wins:\s+1\s+\(25\.0%\)`), "the Mafia win rate should be reported")
+		Expect(output.String()).To(MatchRegexp(`Unfinished games:\s+1\s+\(25\.0%\)`), "the rate of unfinished games should be reported")
+		// 12 phases over 3 finished games is 4 phases, or 2 rounds, per game
+		Expect(output.String()).To(MatchRegexp(`Average rounds per finished game:\s+2\.00\n`), "the average length of the finished games should be reported in rounds")
+		Expect(output.String()).To(MatchRegexp(`Phases stalled by ties:\s+153\s+\(72\.2% of 212 phases\)`), "the rate of stalled phases should be reported")
+	})
+
+	It("does not report the length of finished games when none finished", func() {
+		gameReport := &report{}
+		gameReport.add(&gameResult{mafiaCount: 1, phases: 10})
+
+		output := &bytes.Buffer{}
+		gameReport.print(output)
+		Expect(output.String()).To(MatchRegexp(`Unfinished games:\s+1\s+\(100\.0%\)`), "the only game should be reported as unfinished")
+		Expect(output.String()).ToNot(ContainSubstring("Average rounds"), "there are no finished games to average")
+	})
+
+	DescribeTable("formats percentages",
+		func(count int, total int, expected string) {
+			Expect(percentage(count, total)).To(Equal(expected), "unexpected percentage")
+		},
+		Entry("none", 0, 10, "0.0%"),
+		Entry("all", 10, 10, "100.0%"),
+		Entry("rounded", 1, 3, "33.3%"),
+		Entry("no total", 0, 0, "n/a"),
+	)
+})
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sort"
+
+	"github.com/jrh3k5/mafia-dapp-http/bots"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+const hostAddress = "simulationhost"
+
+type simulator struct {
+	config           *game.GameConfig
+	playerCount      int
+	civilianStrategy bots.Strategy
+	mafiaStrategy    bots.Strategy
+	// maxPhases is the number of phases after which a game is abandoned
+	maxPhases int
+	logger    *slog.Logger
+}
+
+// gameResult describes how a single simulated game played out
+type gameResult struct {
+	outcome game.PhaseOutcome
+	// finished is false if the game was abandoned before either faction won
+	finished   bool
+	mafiaCount int
+	// phases is the number of phases executed over the course of the game
+	phases int
+	// stalledPhases is the number of phases in which votes were cast but no one was eliminated
+	stalledPhases int
+}
+
+// simulate plays a single game to completion, with each bot voting in turn during every phase
+func (s *simulator) simulate(ctx context.Context) (*gameResult, error) {
+	// a fresh engine keeps finished games from accumulating in the archive
+	gameEngine := game.NewInMemoryGameEngine(s.logger)
+
+	if err := gameEngine.InitializeGame(ctx, hostAddress, s.config); err != nil {
+		return nil, fmt.Errorf("failed to initialize game: %w", err)
+	}
+
+	for playerIndex := 0; playerIndex < s.playerCount; playerIndex++ {
+		playerAddress := fmt.Sprintf("bot%04d", playerIndex+1)
+		if err := gameEngine.JoinGame(ctx, hostAddress, playerAddress, fmt.Sprintf("Bot %d", playerIndex+1)); err != nil {
+			return nil, fmt.Errorf("failed to join '%s' to game: %w", playerAddress, err)
+		}
+	}
+
+	if err := gameEngine.StartGame(ctx, hostAddress); err != nil {
+		return nil, fmt.Errorf("failed to start game: %w", err)
+	}
+
+	players, err := gameEngine.GetPlayers(ctx, hostAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get players: %w", err)
+	}
+
+	// the players are listed in no particular order, so sort them so that a seeded batch always votes in the same order
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].PlayerAddress < players[j].PlayerAddress
+	})
+
+	result := &gameResult{}
+	for _, player := range players {
+		if player.PlayerRole == game.PlayerRoleMafia {
+			result.mafiaCount++
+		}
+	}
+
+	currentPhase := s.config.StartingPhase
+	for result.phases < s.maxPhases {
+		if err := s.vote(ctx, gameEngine, players, currentPhase); err != nil {
+			return nil, err
+		}
+
+		votes, err := gameEngine.GetVotes(ctx, hostAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get votes: %w", err)
+		}
+
+		phaseExecution, err := gameEngine.ExecutePhase(ctx, hostAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute phase %d: %w", result.phases+1, err)
+		}
+
+		result.phases++
+
+		switch currentPhase {
+		case game.TimeOfDayDay:
+			if len(votes.MafiaAccusations) > 0 && len(phaseExecution.ConvictedPlayers) == 0 {
+				result.stalledPhases++
+			}
+			currentPhase = game.TimeOfDayNight
+		case game.TimeOfDayNight:
+			if len(votes.KillVotes) > 0 && len(phaseExecution.KilledPlayers) == 0 {
+				result.stalledPhases++
+			}
+			currentPhase = game.TimeOfDayDay
+		}
+
+		if phaseExecution.PhaseOutcome != game.PhaseOutcomeContinuation {
+			result.outcome = phaseExecution.PhaseOutcome
+			result.finished = true
+			break
+		}
+	}
+
+	if err := gameEngine.FinishGame(ctx, hostAddress); err != nil {
+		return nil, fmt.Errorf("failed to finish game: %w", err)
+	}
+
+	return result, nil
+}
+
+// vote has every player vote in a random order, so that no bot always gets to see the votes of the others
+func (s *simulator) vote(ctx context.Context, gameEngine game.Engine, players []*game.Player, currentPhase game.TimeOfDay) error {
+	votingOrder := rand.Perm(len(players))
+	for _, playerIndex := range votingOrder {
+		view, err := bots.BuildView(ctx, gameEngine, hostAddress, players[playerIndex].PlayerAddress)
+		if err != nil {
+			return err
+		}
+
+		strategy := s.civilianStrategy
+		if view.Self.PlayerRole == game.PlayerRoleMafia {
+			strategy = s.mafiaStrategy
+		}
+
+		if err := bots.Vote(ctx, gameEngine, hostAddress, view, currentPhase, strategy); err != nil {
+			return fmt.Errorf("failed to vote on behalf of '%s': %w", view.Self.PlayerAddress, err)
+		}
+	}
+
+	return nil
+}
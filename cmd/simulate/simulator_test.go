@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"regexp"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jrh3k5/mafia-dapp-http/bots"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// rotationStrategy has every bot accuse the next player by address, so that every player is accused exactly once and the day always ends in a tie
+type rotationStrategy struct{}
+
+func (*rotationStrategy) ChooseAccusation(view *bots.View) (string, bool) {
+	for _, candidate := range view.Candidates {
+		if candidate.PlayerAddress > view.Self.PlayerAddress {
+			return candidate.PlayerAddress, true
+		}
+	}
+
+	return view.Candidates[0].PlayerAddress, true
+}
+
+func (*rotationStrategy) ChooseKill(view *bots.View) (string, bool) {
+	return view.Candidates[0].PlayerAddress, true
+}
+
+var _ = Describe("simulator", func() {
+	It("counts the phases stalled by ties", func() {
+		gameConfig := game.DefaultGameConfig()
+		gameConfig.MinPlayers = 6
+		gameConfig.MafiaCount = 1
+		gameConfig.TiePolicy = game.TiePolicyNoElimination
+
+		gameSimulator := &simulator{
+			config:           gameConfig,
+			playerCount:      6,
+			civilianStrategy: &rotationStrategy{},
+			mafiaStrategy:    &rotationStrategy{},
+			maxPhases:        4,
+			logger:           slog.New(slog.NewTextHandler(GinkgoWriter, &slog.HandlerOptions{Level: slog.LevelWarn})),
+		}
+
+		result, err := gameSimulator.simulate(context.Background())
+		Expect(err).ToNot(HaveOccurred(), "simulating the game should not fail")
+		Expect(result.mafiaCount).To(Equal(1), "the game should have the configured number of Mafia members")
+		Expect(result.phases).To(Equal(4), "the game should be abandoned after the maximum number of phases")
+		Expect(result.finished).To(BeFalse(), "the Mafia member should not have been caught by tied accusations")
+		Expect(result.stalledPhases).To(Equal(2), "every day should be stalled by a tie, and no night should be")
+	})
+
+	It("reports a fixed-seed batch of games consistently", func() {
+		args := []string{"-games", "20", "-players", "6", "-seed", "42", "-civilian-strategy", "crowd", "-mafia-strategy", "mafia"}
+
+		firstOutput := &bytes.Buffer{}
+		Expect(run(args, firstOutput, GinkgoWriter)).To(Succeed(), "simulating the batch should not fail")
+
+		// reportedCount reads the count reported on the line with the given label
+		reportedCount := func(label string) int {
+			match := regexp.MustCompile(regexp.QuoteMeta(label) + `:\s+(\d+)`).FindStringSubmatch(firstOutput.String())
+			Expect(match).ToNot(BeNil(), "the report should include '%s'", label)
+			count, err := strconv.Atoi(match[1])
+			Expect(err).ToNot(HaveOccurred(), "the count reported for '%s' should be a number", label)
+			return count
+		}
+
+		Expect(firstOutput.String()).To(HavePrefix("Seed: 42\n"), "the seed should be reported so that the batch can be repeated")
+		Expect(reportedCount("Games simulated")).To(Equal(20), "every game should be simulated")
+		Expect(reportedCount("Games with 2 Mafia members")).To(Equal(20), "every game of 6 players should have 2 Mafia members")
+		Expect(reportedCount("Civilian wins")+reportedCount("Mafia wins")+reportedCount("Unfinished games")).To(Equal(20), "every game should be won by one side or left unfinished")
+
+		secondOutput := &bytes.Buffer{}
+		Expect(run(args, secondOutput, GinkgoWriter)).To(Succeed(), "simulating the batch again should not fail")
+		Expect(secondOutput.String()).To(Equal(firstOutput.String()), "the same seed should produce the same report")
+	})
+
+	It("rejects configurations that cannot be simulated", func() {
+		Expect(run([]string{"-games", "0"}, &bytes.Buffer{}, GinkgoWriter)).To(MatchError(ContainSubstring("the number of games must be positive")), "a batch without games should be rejected")
+		Expect(run([]string{"-players", "4", "-mafia-ratio", "3"}, &bytes.Buffer{}, GinkgoWriter)).To(MatchError(ContainSubstring("invalid game configuration")), "games that the Mafia win immediately should be rejected")
+		Expect(run([]string{"-mafia-strategy", "cunning"}, &bytes.Buffer{}, GinkgoWriter)).To(MatchError(ContainSubstring("unknown bot strategy")), "unknown strategies should be rejected")
+	})
+})
@@ -0,0 +1,332 @@
+// Package config reads the configuration of the server from flags, environment variables, and an optional YAML file.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EngineBackendMemory keeps all games in memory, losing them when the server stops
+const EngineBackendMemory = "memory"
+
+// LogLevel is the least severe level of messages that are logged
+type LogLevel string
+
+const LogLevelDebug LogLevel = "debug"
+const LogLevelInfo LogLevel = "info"
+const LogLevelWarn LogLevel = "warn"
+const LogLevelError LogLevel = "error"
+
+// LogFormat is how each logged message is written
+type LogFormat string
+
+const LogFormatText LogFormat = "text"
+const LogFormatJSON LogFormat = "json"
+
+// Config is the configuration of the server
+type Config struct {
+	ListenAddress string `yaml:"listenAddress"`
+	// WaitTimeout is the longest that a long poll, such as for the start of a game, is held open before failing
+	WaitTimeout time.Duration `yaml:"waitTimeout"`
+	// AllowedOrigins are the origins from which browsers may call the server; "*" allows every origin
+	AllowedOrigins []string `yaml:"allowedOrigins"`
+	// AllowedMethods are the methods that browsers may use in cross-origin requests, wherever a route supports them
+	AllowedMethods []string `yaml:"allowedMethods"`
+	// AllowedHeaders are the request headers that browsers may send in cross-origin requests; "*" allows any header
+	AllowedHeaders []string `yaml:"allowedHeaders"`
+	// AllowCredentials allows browsers to send cookies and authorization headers in cross-origin requests
+	AllowCredentials bool `yaml:"allowCredentials"`
+	// CORSMaxAge is how long browsers may cache the response to a preflight request
+	CORSMaxAge    time.Duration `yaml:"corsMaxAge"`
+	EngineBackend string        `yaml:"engineBackend"`
+	LogLevel      LogLevel      `yaml:"logLevel"`
+	LogFormat     LogFormat     `yaml:"logFormat"`
+	// ShutdownTimeout is the longest that the server waits for requests to finish once it has been told to stop
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+	// SnapshotPath is the file to which the state of every game is written when the server stops; if blank, no snapshot is written
+	SnapshotPath string `yaml:"snapshotPath"`
+	// DevMode enables the admin endpoints that force games into particular states and inject faults into requests
+	DevMode bool `yaml:"devMode"`
+	// AdminToken is the bearer token that every request to the admin endpoints must carry; it is required in dev mode
+	AdminToken string `yaml:"adminToken"`
+}
+
+// Default builds the configuration used when nothing else is configured
+func Default() *Config {
+	return &Config{
+		ListenAddress:   "0.0.0.0:3000",
+		WaitTimeout:     10 * time.Minute,
+		AllowedOrigins:  []string{"*"},
+		AllowedMethods:  []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+		AllowedHeaders:  []string{"*"},
+		CORSMaxAge:      10 * time.Minute,
+		EngineBackend:   EngineBackendMemory,
+		LogLevel:        LogLevelInfo,
+		LogFormat:       LogFormatText,
+		ShutdownTimeout: 30 * time.Second,
+	}
+}
+
+// Load builds the configuration from the given command-line arguments, the environment, and the configuration file, if any.
+// Settings given as flags override those given as environment variables, which override those in the configuration file.
+// The configuration file is named by the -config flag or the MAFIA_CONFIG environment variable.
+func Load(args []string, lookupEnv func(string) (string, bool), output io.Writer) (*Config, error) {
+	flags := flag.NewFlagSet("mafia-dapp-http", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	defaultConfig := Default()
+	configPath := flags.String("config", "", "path to a YAML configuration file (env MAFIA_CONFIG)")
+	listenAddress := flags.String("listen", defaultConfig.ListenAddress, "address on which to listen for requests (env MAFIA_LISTEN_ADDRESS)")
+	waitTimeout := flags.Duration("wait-timeout", defaultConfig.WaitTimeout, "longest that a long poll is held open (env MAFIA_WAIT_TIMEOUT)")
+	allowedOrigins := flags.String("allowed-origins", strings.Join(defaultConfig.AllowedOrigins, ","), "comma-separated origins from which browsers may call the server (env MAFIA_ALLOWED_ORIGINS)")
+	allowedMethods := flags.String("allowed-methods", strings.Join(defaultConfig.AllowedMethods, ","), "comma-separated methods that browsers may use in cross-origin requests (env MAFIA_ALLOWED_METHODS)")
+	allowedHeaders := flags.String("allowed-headers", strings.Join(defaultConfig.AllowedHeaders, ","), "comma-separated headers that browsers may send in cross-origin requests (env MAFIA_ALLOWED_HEADERS)")
+	allowCredentials := flags.Bool("allow-credentials", defaultConfig.AllowCredentials, "allow browsers to send credentials in cross-origin requests (env MAFIA_ALLOW_CREDENTIALS)")
+	corsMaxAge := flags.Duration("cors-max-age", defaultConfig.CORSMaxAge, "how long browsers may cache preflight responses (env MAFIA_CORS_MAX_AGE)")
+	engineBackend := flags.String("engine", defaultConfig.EngineBackend, "where games are stored; only memory is supported (env MAFIA_ENGINE_BACKEND)")
+	logLevel := flags.String("log-level", string(defaultConfig.LogLevel), "least severe level of messages to log: debug, info, warn, or error (env MAFIA_LOG_LEVEL)")
+	logFormat := flags.String("log-format", string(defaultConfig.LogFormat), "format in which to log messages: text or json (env MAFIA_LOG_FORMAT)")
+	shutdownTimeout := flags.Duration("shutdown-timeout", defaultConfig.ShutdownTimeout, "longest to wait for requests to finish when stopping (env MAFIA_SHUTDOWN_TIMEOUT)")
+	snapshotPath := flags.String("snapshot-path", defaultConfig.SnapshotPath, "file to which the state of every game is written when stopping (env MAFIA_SNAPSHOT_PATH)")
+	devMode := flags.Bool("dev", defaultConfig.DevMode, "enable the admin endpoints that force game state and inject faults (env MAFIA_DEV_MODE)")
+	adminToken := flags.String("admin-token", defaultConfig.AdminToken, "bearer token required by the admin endpoints; required in dev mode (env MAFIA_ADMIN_TOKEN)")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	setFlags := make(map[string]bool)
+	flags.Visit(func(setFlag *flag.Flag) {
+		setFlags[setFlag.Name] = true
+	})
+
+	config := defaultConfig
+
+	if !setFlags["config"] {
+		*configPath, _ = lookupEnv("MAFIA_CONFIG")
+	}
+	if *configPath != "" {
+		if err := config.readFile(*configPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.readEnv(lookupEnv); err != nil {
+		return nil, err
+	}
+
+	if setFlags["listen"] {
+		config.ListenAddress = *listenAddress
+	}
+	if setFlags["wait-timeout"] {
+		config.WaitTimeout = *waitTimeout
+	}
+	if setFlags["allowed-origins"] {
+		config.AllowedOrigins = splitList(*allowedOrigins)
+	}
+	if setFlags["allowed-methods"] {
+		config.AllowedMethods = splitList(*allowedMethods)
+	}
+	if setFlags["allowed-headers"] {
+		config.AllowedHeaders = splitList(*allowedHeaders)
+	}
+	if setFlags["allow-credentials"] {
+		config.AllowCredentials = *allowCredentials
+	}
+	if setFlags["cors-max-age"] {
+		config.CORSMaxAge = *corsMaxAge
+	}
+	if setFlags["engine"] {
+		config.EngineBackend = *engineBackend
+	}
+	if setFlags["log-level"] {
+		config.LogLevel = LogLevel(*logLevel)
+	}
+	if setFlags["log-format"] {
+		config.LogFormat = LogFormat(*logFormat)
+	}
+	if setFlags["shutdown-timeout"] {
+		config.ShutdownTimeout = *shutdownTimeout
+	}
+	if setFlags["snapshot-path"] {
+		config.SnapshotPath = *snapshotPath
+	}
+	if setFlags["dev"] {
+		config.DevMode = *devMode
+	}
+	if setFlags["admin-token"] {
+		config.AdminToken = *adminToken
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return config, nil
+}
+
+// Validate determines whether the server can run with the configuration
+func (c *Config) Validate() error {
+	if c.ListenAddress == "" {
+		return errors.New("a listen address must be supplied")
+	}
+
+	if c.WaitTimeout <= 0 {
+		return fmt.Errorf("the wait timeout must be positive, not %v", c.WaitTimeout)
+	}
+
+	if len(c.AllowedOrigins) == 0 {
+		return errors.New("at least one allowed origin must be supplied")
+	}
+
+	if len(c.AllowedMethods) == 0 {
+		return errors.New("at least one allowed method must be supplied")
+	}
+
+	if c.CORSMaxAge < 0 {
+		return fmt.Errorf("the CORS max age cannot be negative, not %v", c.CORSMaxAge)
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		return fmt.Errorf("the shutdown timeout must be positive, not %v", c.ShutdownTimeout)
+	}
+
+	if c.EngineBackend != EngineBackendMemory {
+		return fmt.Errorf("unknown engine backend: '%s'", c.EngineBackend)
+	}
+
+	switch c.LogLevel {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+	default:
+		return fmt.Errorf("unknown log level: '%s'", c.LogLevel)
+	}
+
+	switch c.LogFormat {
+	case LogFormatText, LogFormatJSON:
+	default:
+		return fmt.Errorf("unknown log format: '%s'", c.LogFormat)
+	}
+
+	if c.DevMode && c.AdminToken == "" {
+		return errors.New("an admin token must be supplied in dev mode, so that the admin endpoints cannot be called by anyone who can reach the server")
+	}
+
+	return nil
+}
+
+func (c *Config) readEnv(lookupEnv func(string) (string, bool)) error {
+	if listenAddress, hasValue := lookupEnv("MAFIA_LISTEN_ADDRESS"); hasValue {
+		c.ListenAddress = listenAddress
+	}
+
+	if waitTimeout, hasValue := lookupEnv("MAFIA_WAIT_TIMEOUT"); hasValue {
+		parsedTimeout, err := time.ParseDuration(waitTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid MAFIA_WAIT_TIMEOUT: %w", err)
+		}
+		c.WaitTimeout = parsedTimeout
+	}
+
+	if allowedOrigins, hasValue := lookupEnv("MAFIA_ALLOWED_ORIGINS"); hasValue {
+		c.AllowedOrigins = splitList(allowedOrigins)
+	}
+
+	if allowedMethods, hasValue := lookupEnv("MAFIA_ALLOWED_METHODS"); hasValue {
+		c.AllowedMethods = splitList(allowedMethods)
+	}
+
+	if allowedHeaders, hasValue := lookupEnv("MAFIA_ALLOWED_HEADERS"); hasValue {
+		c.AllowedHeaders = splitList(allowedHeaders)
+	}
+
+	if allowCredentials, hasValue := lookupEnv("MAFIA_ALLOW_CREDENTIALS"); hasValue {
+		parsedAllowCredentials, err := strconv.ParseBool(allowCredentials)
+		if err != nil {
+			return fmt.Errorf("invalid MAFIA_ALLOW_CREDENTIALS: %w", err)
+		}
+		c.AllowCredentials = parsedAllowCredentials
+	}
+
+	if corsMaxAge, hasValue := lookupEnv("MAFIA_CORS_MAX_AGE"); hasValue {
+		parsedMaxAge, err := time.ParseDuration(corsMaxAge)
+		if err != nil {
+			return fmt.Errorf("invalid MAFIA_CORS_MAX_AGE: %w", err)
+		}
+		c.CORSMaxAge = parsedMaxAge
+	}
+
+	if engineBackend, hasValue := lookupEnv("MAFIA_ENGINE_BACKEND"); hasValue {
+		c.EngineBackend = engineBackend
+	}
+
+	if logLevel, hasValue := lookupEnv("MAFIA_LOG_LEVEL"); hasValue {
+		c.LogLevel = LogLevel(logLevel)
+	}
+
+	if logFormat, hasValue := lookupEnv("MAFIA_LOG_FORMAT"); hasValue {
+		c.LogFormat = LogFormat(logFormat)
+	}
+
+	if shutdownTimeout, hasValue := lookupEnv("MAFIA_SHUTDOWN_TIMEOUT"); hasValue {
+		parsedTimeout, err := time.ParseDuration(shutdownTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid MAFIA_SHUTDOWN_TIMEOUT: %w", err)
+		}
+		c.ShutdownTimeout = parsedTimeout
+	}
+
+	if snapshotPath, hasValue := lookupEnv("MAFIA_SNAPSHOT_PATH"); hasValue {
+		c.SnapshotPath = snapshotPath
+	}
+
+	if devMode, hasValue := lookupEnv("MAFIA_DEV_MODE"); hasValue {
+		parsedDevMode, err := strconv.ParseBool(devMode)
+		if err != nil {
+			return fmt.Errorf("invalid MAFIA_DEV_MODE: %w", err)
+		}
+		c.DevMode = parsedDevMode
+	}
+
+	if adminToken, hasValue := lookupEnv("MAFIA_ADMIN_TOKEN"); hasValue {
+		c.AdminToken = adminToken
+	}
+
+	return nil
+}
+
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	// reject typos rather than silently falling back to defaults
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse configuration file '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+// splitList splits a comma-separated list, ignoring blank entries
+func splitList(list string) []string {
+	var entries []string
+	for _, entry := range strings.Split(list, ",") {
+		if trimmedEntry := strings.TrimSpace(entry); trimmedEntry != "" {
+			entries = append(entries, trimmedEntry)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,139 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jrh3k5/mafia-dapp-http/config"
+)
+
+var _ = Describe("Load", func() {
+	// writeConfigFile writes the given YAML to a configuration file that is removed once the test ends, returning its path
+	writeConfigFile := func(contents string) string {
+		configPath := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(configPath, []byte(contents), 0o600)).To(Succeed(), "writing the configuration file should not fail")
+		return configPath
+	}
+
+	// envLookup looks up variables in the given environment instead of the real one
+	envLookup := func(env map[string]string) func(string) (string, bool) {
+		return func(name string) (string, bool) {
+			value, hasValue := env[name]
+			return value, hasValue
+		}
+	}
+
+	const fileContents = `
+listenAddress: file:3000
+waitTimeout: 1m
+logLevel: warn
+devMode: true
+adminToken: file-token
+`
+
+	DescribeTable("resolves each setting from flags, then the environment, then the configuration file",
+		func(useFile bool, args []string, env map[string]string, verify func(loadedConfig *config.Config)) {
+			if useFile {
+				args = append([]string{"-config", writeConfigFile(fileContents)}, args...)
+			}
+
+			loadedConfig, err := config.Load(args, envLookup(env), GinkgoWriter)
+			Expect(err).ToNot(HaveOccurred(), "loading the configuration should not fail")
+			verify(loadedConfig)
+		},
+		Entry("defaults", false, nil, nil, func(loadedConfig *config.Config) {
+			Expect(loadedConfig).To(Equal(config.Default()), "nothing configured should leave the defaults")
+		}),
+		Entry("configuration file only", true, nil, nil, func(loadedConfig *config.Config) {
+			Expect(loadedConfig.ListenAddress).To(Equal("file:3000"), "the listen address should be read from the file")
+			Expect(loadedConfig.WaitTimeout).To(Equal(time.Minute), "the wait timeout should be read from the file")
+			Expect(loadedConfig.LogLevel).To(Equal(config.LogLevelWarn), "the log level should be read from the file")
+			Expect(loadedConfig.DevMode).To(BeTrue(), "dev mode should be read from the file")
+			Expect(loadedConfig.AdminToken).To(Equal("file-token"), "the admin token should be read from the file")
+			Expect(loadedConfig.LogFormat).To(Equal(config.LogFormatText), "settings not in the file should be left at their defaults")
+		}),
+		Entry("environment over configuration file", true, nil, map[string]string{
+			"MAFIA_LISTEN_ADDRESS": "env:3000",
+			"MAFIA_DEV_MODE":       "false",
+		}, func(loadedConfig *config.Config) {
+			Expect(loadedConfig.ListenAddress).To(Equal("env:3000"), "the environment should override the file")
+			Expect(loadedConfig.DevMode).To(BeFalse(), "the environment should be able to turn off what the file turned on")
+			Expect(loadedConfig.WaitTimeout).To(Equal(time.Minute), "settings not in the environment should be read from the file")
+		}),
+		Entry("flags over environment and configuration file", true, []string{"-listen", "flag:3000", "-wait-timeout", "5s"}, map[string]string{
+			"MAFIA_LISTEN_ADDRESS": "env:3000",
+			"MAFIA_LOG_FORMAT":     "json",
+		}, func(loadedConfig *config.Config) {
+			Expect(loadedConfig.ListenAddress).To(Equal("flag:3000"), "flags should override the environment")
+			Expect(loadedConfig.WaitTimeout).To(Equal(5*time.Second), "flags should override the file")
+			Expect(loadedConfig.LogFormat).To(Equal(config.LogFormatJSON), "settings not given as flags should be read from the environment")
+			Expect(loadedConfig.LogLevel).To(Equal(config.LogLevelWarn), "settings given only in the file should be read from the file")
+		}),
+		Entry("admin token from flags over environment", false, []string{"-dev", "-admin-token", "flag-token"}, map[string]string{
+			"MAFIA_ADMIN_TOKEN": "env-token",
+		}, func(loadedConfig *config.Config) {
+			Expect(loadedConfig.AdminToken).To(Equal("flag-token"), "the flag should override the environment")
+		}),
+		Entry("flags left at their defaults do not override", true, []string{"-dev=true"}, map[string]string{
+			"MAFIA_WAIT_TIMEOUT": "2m",
+		}, func(loadedConfig *config.Config) {
+			Expect(loadedConfig.WaitTimeout).To(Equal(2*time.Minute), "an unset flag should not override the environment with its default")
+			Expect(loadedConfig.ListenAddress).To(Equal("file:3000"), "an unset flag should not override the file with its default")
+		}),
+		Entry("lists split on commas", false, []string{"-allowed-origins", "https://a.example, https://b.example,"}, map[string]string{
+			"MAFIA_ALLOWED_HEADERS": "Content-Type,X-Request-ID",
+		}, func(loadedConfig *config.Config) {
+			Expect(loadedConfig.AllowedOrigins).To(Equal([]string{"https://a.example", "https://b.example"}), "blank entries and spaces should be dropped")
+			Expect(loadedConfig.AllowedHeaders).To(Equal([]string{"Content-Type", "X-Request-ID"}), "lists should be read from the environment")
+		}),
+	)
+
+	It("reads the configuration file named by MAFIA_CONFIG", func() {
+		loadedConfig, err := config.Load(nil, envLookup(map[string]string{
+			"MAFIA_CONFIG": writeConfigFile(fileContents),
+		}), GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred(), "loading the configuration should not fail")
+		Expect(loadedConfig.ListenAddress).To(Equal("file:3000"), "the file named by the environment should be read")
+	})
+
+	It("prefers the configuration file named by the flag over MAFIA_CONFIG", func() {
+		loadedConfig, err := config.Load([]string{"-config", writeConfigFile("listenAddress: flagfile:3000\n")}, envLookup(map[string]string{
+			"MAFIA_CONFIG": writeConfigFile(fileContents),
+		}), GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred(), "loading the configuration should not fail")
+		Expect(loadedConfig.ListenAddress).To(Equal("flagfile:3000"), "the file named by the flag should be read")
+		Expect(loadedConfig.DevMode).To(BeFalse(), "the file named by the environment should not be read")
+	})
+
+	It("reads an empty configuration file as the defaults", func() {
+		loadedConfig, err := config.Load([]string{"-config", writeConfigFile("")}, envLookup(nil), GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred(), "loading an empty configuration file should not fail")
+		Expect(loadedConfig).To(Equal(config.Default()), "an empty file should leave the defaults")
+	})
+
+	DescribeTable("rejects configurations that cannot be used",
+		func(fileContents string, args []string, env map[string]string, expectedMessage string) {
+			if fileContents != "" {
+				args = append([]string{"-config", writeConfigFile(fileContents)}, args...)
+			}
+
+			_, err := config.Load(args, envLookup(env), GinkgoWriter)
+			Expect(err).To(MatchError(ContainSubstring(expectedMessage)), "the configuration should be rejected")
+		},
+		Entry("unknown field in the configuration file", "listenAdress: typo:3000\n", nil, nil, "field listenAdress not found"),
+		Entry("malformed configuration file", "waitTimeout: [\n", nil, nil, "failed to parse configuration file"),
+		Entry("unparseable duration in the configuration file", "waitTimeout: soon\n", nil, nil, "failed to parse configuration file"),
+		Entry("missing configuration file", "", []string{"-config", "/nonexistent/config.yaml"}, nil, "failed to read configuration file"),
+		Entry("unparseable duration in the environment", "", nil, map[string]string{"MAFIA_WAIT_TIMEOUT": "soon"}, "invalid MAFIA_WAIT_TIMEOUT"),
+		Entry("unparseable boolean in the environment", "", nil, map[string]string{"MAFIA_DEV_MODE": "maybe"}, "invalid MAFIA_DEV_MODE"),
+		Entry("unparseable flag", "", []string{"-wait-timeout", "soon"}, nil, "invalid value"),
+		Entry("unknown flag", "", []string{"-verbose"}, nil, "flag provided but not defined"),
+		Entry("invalid setting", "", []string{"-log-level", "loud"}, nil, "unknown log level"),
+		Entry("invalid setting from the file", "waitTimeout: 0s\n", nil, nil, "the wait timeout must be positive"),
+		Entry("dev mode without an admin token", "", []string{"-dev"}, nil, "an admin token must be supplied in dev mode"),
+	)
+})
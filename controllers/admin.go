@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// NewAdminAuthMiddleware builds a handler that rejects any request that does not carry the given token as a bearer token in its Authorization header.
+// The admin endpoints bypass the rules of the game, so they must never be reachable without it.
+func NewAdminAuthMiddleware(adminToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestToken, hasToken := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		// an empty token is never accepted, even if the server was somehow built without one
+		if !hasToken || adminToken == "" || subtle.ConstantTimeCompare([]byte(requestToken), []byte(adminToken)) != 1 {
+			c.Header("WWW-Authenticate", "Bearer")
+			abortWithErrorResponse(c, http.StatusUnauthorized, errorCodeUnauthorized, "the admin token must be supplied as a bearer token in the Authorization header")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// NewAdminClearVotesHandler builds a handler that discards all votes cast in the current phase of a game
+func NewAdminClearVotesHandler(adminEngine game.AdminEngine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress := c.Param("hostAddress")
+		if hostAddress == "" {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "hostAddress must be supplied")
+			return
+		}
+
+		if err := adminEngine.ClearVotes(c.Request.Context(), hostAddress); err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// NewAdminGetVotesHandler builds a handler that shows the votes cast so far in the current phase of a game without requiring a spectator
+func NewAdminGetVotesHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress := c.Param("hostAddress")
+		if hostAddress == "" {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "hostAddress must be supplied")
+			return
+		}
+
+		votes, err := gameEngine.GetVotes(c.Request.Context(), hostAddress)
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &votesResponse{
+			MafiaAccusations: votes.MafiaAccusations,
+			KillVotes:        votes.KillVotes,
+		})
+	}
+}
+
+// NewAdminInjectPhaseExecutionHandler builds a handler that sends a synthetic phase execution, read from the request body, to everyone waiting on the current phase of a game
+func NewAdminInjectPhaseExecutionHandler(adminEngine game.AdminEngine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress := c.Param("hostAddress")
+		if hostAddress == "" {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "hostAddress must be supplied")
+			return
+		}
+
+		var request phaseExecutionResponse
+		if err := c.ShouldBindJSON(&request); err != nil {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, err.Error())
+			return
+		}
+
+		phaseExecution := &game.PhaseExecution{
+			PhaseOutcome:     game.PhaseOutcome(request.PhaseOutcome),
+			CurrentPhase:     game.TimeOfDay(request.CurrentPhase),
+			KilledPlayers:    request.KilledPlayers,
+			ConvictedPlayers: request.ConvictedPlayers,
+			ForfeitedPlayers: request.ForfeitedPlayers,
+		}
+		if err := adminEngine.InjectPhaseExecution(c.Request.Context(), hostAddress, phaseExecution); err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// NewAdminSetCurrentPhaseHandler builds a handler that changes the time of day of a game
+func NewAdminSetCurrentPhaseHandler(adminEngine game.AdminEngine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress := c.Param("hostAddress")
+		if hostAddress == "" {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "hostAddress must be supplied")
+			return
+		}
+
+		timeOfDay, err := strconv.Atoi(c.Query("timeOfDay"))
+		if err != nil {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "timeOfDay must be 0 (day) or 1 (night)")
+			return
+		}
+
+		if err := adminEngine.SetCurrentPhase(c.Request.Context(), hostAddress, game.TimeOfDay(timeOfDay)); err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// NewAdminSetPlayerRoleHandler builds a handler that assigns a specific role to a player
+func NewAdminSetPlayerRoleHandler(adminEngine game.AdminEngine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress := c.Param("hostAddress")
+		if hostAddress == "" {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "hostAddress must be supplied")
+			return
+		}
+
+		playerAddress := c.Param("playerAddress")
+		if playerAddress == "" {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "playerAddress must be supplied")
+			return
+		}
+
+		playerRole, err := strconv.Atoi(c.Query("playerRole"))
+		if err != nil {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "playerRole must be 0 (civilian) or 1 (Mafia)")
+			return
+		}
+
+		if err := adminEngine.SetPlayerRole(c.Request.Context(), hostAddress, playerAddress, game.PlayerRole(playerRole)); err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// NewAdminSetPlayerStatusHandler builds a handler that marks a player as dead and/or convicted.
+// Either status that is not supplied is cleared.
+func NewAdminSetPlayerStatusHandler(adminEngine game.AdminEngine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress := c.Param("hostAddress")
+		if hostAddress == "" {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "hostAddress must be supplied")
+			return
+		}
+
+		playerAddress := c.Param("playerAddress")
+		if playerAddress == "" {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "playerAddress must be supplied")
+			return
+		}
+
+		var dead bool
+		if deadParam := c.Query("dead"); deadParam != "" {
+			parsedDead, err := strconv.ParseBool(deadParam)
+			if err != nil {
+				abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "dead must be a boolean")
+				return
+			}
+			dead = parsedDead
+		}
+
+		var convicted bool
+		if convictedParam := c.Query("convicted"); convictedParam != "" {
+			parsedConvicted, err := strconv.ParseBool(convictedParam)
+			if err != nil {
+				abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "convicted must be a boolean")
+				return
+			}
+			convicted = parsedConvicted
+		}
+
+		if err := adminEngine.SetPlayerStatus(c.Request.Context(), hostAddress, playerAddress, dead, convicted); err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// NewGetArchivedGameHandler builds a handler that returns the full record of a finished or cancelled game
+func NewGetArchivedGameHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		gameID := c.Param("gameID")
+		if gameID == "" {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		archivedGame, err := gameEngine.GetArchivedGame(c.Request.Context(), gameID)
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		if archivedGame == nil {
+			abortWithErrorResponse(c, http.StatusNotFound, errorCodeNotFound, fmt.Sprintf("no archived game found for ID '%s'", gameID))
+			return
+		}
+
+		players := make([]*playerResponse, len(archivedGame.Players))
+		for playerIndex, player := range archivedGame.Players {
+			// the game is over, so every role can be revealed
+			playerRoleInt := int(player.PlayerRole)
+			players[playerIndex] = &playerResponse{
+				PlayerAddress:  player.PlayerAddress,
+				PlayerNickname: player.PlayerNickname,
+				PlayerRole:     &playerRoleInt,
+				Dead:           &player.Dead,
+				Convicted:      &player.Convicted,
+				Forfeited:      &player.Forfeited,
+			}
+		}
+
+		phaseExecutions := make([]*phaseExecutionResponse, len(archivedGame.PhaseExecutions))
+		for phaseIndex, phaseExecution := range archivedGame.PhaseExecutions {
+			phaseExecutions[phaseIndex] = toPhaseExecutionResponse(phaseExecution)
+		}
+
+		c.JSON(http.StatusOK, &archivedGameResponse{
+			archivedGameSummaryResponse: toArchivedGameSummaryResponse(archivedGame),
+			Players:                     players,
+			PhaseExecutions:             phaseExecutions,
+		})
+	}
+}
+
+// NewGetArchivedGamesHandler builds a handler that lists all finished and cancelled games
+func NewGetArchivedGamesHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		archivedGames, err := gameEngine.GetArchivedGames(c.Request.Context())
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		summaries := make([]*archivedGameSummaryResponse, len(archivedGames))
+		for gameIndex, archivedGame := range archivedGames {
+			summaries[gameIndex] = toArchivedGameSummaryResponse(archivedGame)
+		}
+
+		c.JSON(http.StatusOK, summaries)
+	}
+}
+
+func toArchivedGameSummaryResponse(archivedGame *game.ArchivedGame) *archivedGameSummaryResponse {
+	response := &archivedGameSummaryResponse{
+		ID:           archivedGame.ID,
+		HostAddress:  archivedGame.HostAddress,
+		Cancelled:    archivedGame.Cancelled,
+		PhaseOutcome: int(archivedGame.PhaseOutcome),
+		PlayerCount:  len(archivedGame.Players),
+		CreatedAt:    archivedGame.CreatedAt,
+		EndedAt:      archivedGame.EndedAt,
+	}
+
+	if !archivedGame.StartedAt.IsZero() {
+		startedAt := archivedGame.StartedAt
+		response.StartedAt = &startedAt
+	}
+
+	return response
+}
+
+type archivedGameSummaryResponse struct {
+	ID           string     `json:"id"`
+	HostAddress  string     `json:"hostAddress"`
+	Cancelled    bool       `json:"cancelled"`
+	PhaseOutcome int        `json:"phaseOutcome"`
+	PlayerCount  int        `json:"playerCount"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	StartedAt    *time.Time `json:"startedAt,omitempty"`
+	EndedAt      time.Time  `json:"endedAt"`
+}
+
+type archivedGameResponse struct {
+	*archivedGameSummaryResponse
+	Players         []*playerResponse         `json:"players"`
+	PhaseExecutions []*phaseExecutionResponse `json:"phaseExecutions"`
+}
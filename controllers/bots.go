@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/bots"
+)
+
+const defaultBotMinDelay = 500 * time.Millisecond
+const defaultBotMaxDelay = 2 * time.Second
+
+// NewAddBotsHandler builds a handler that adds bot players to a game that has not yet started
+func NewAddBotsHandler(botManager *bots.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress := c.Param("hostAddress")
+		if hostAddress == "" {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "hostAddress must be supplied")
+			return
+		}
+
+		count, err := strconv.Atoi(c.Query("count"))
+		if err != nil || count < 1 || count > bots.MaxBotCount {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, fmt.Sprintf("count must be supplied as an integer from 1 to %d", bots.MaxBotCount))
+			return
+		}
+
+		strategyName := c.DefaultQuery("strategy", "random")
+		strategy, err := bots.NewStrategy(strategyName)
+		if err != nil {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, err.Error())
+			return
+		}
+
+		minDelay, isValid := parseDelay(c, "minDelayMs", defaultBotMinDelay)
+		if !isValid {
+			return
+		}
+
+		maxDelay, isValid := parseDelay(c, "maxDelayMs", defaultBotMaxDelay)
+		if !isValid {
+			return
+		}
+
+		if maxDelay < minDelay {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "maxDelayMs cannot be less than minDelayMs")
+			return
+		}
+
+		botAddresses, err := botManager.AddBots(c.Request.Context(), hostAddress, count, &bots.Options{
+			Strategy: strategy,
+			MinDelay: minDelay,
+			MaxDelay: maxDelay,
+		})
+		if err != nil {
+			// bots that joined before the failure keep playing, so the error tells the caller which they are
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &addBotsResponse{
+			BotAddresses: botAddresses,
+		})
+	}
+}
+
+func parseDelay(c *gin.Context, paramName string, defaultDelay time.Duration) (time.Duration, bool) {
+	delayParam := c.Query(paramName)
+	if delayParam == "" {
+		return defaultDelay, true
+	}
+
+	delayMillis, err := strconv.Atoi(delayParam)
+	if err != nil || delayMillis < 0 {
+		abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, fmt.Sprintf("%s must be a non-negative integer", paramName))
+		return 0, false
+	}
+
+	return time.Duration(delayMillis) * time.Millisecond, true
+}
+
+type addBotsResponse struct {
+	BotAddresses []string `json:"botAddresses"`
+}
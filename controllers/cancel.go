@@ -17,7 +17,7 @@ func NewCancelGameHandler(gameEngine game.Engine) gin.HandlerFunc {
 		}
 
 		if err := gameEngine.CancelGame(c.Request.Context(), hostAddress); err != nil {
-			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			abortWithEngineError(c, err)
 			return
 		}
 
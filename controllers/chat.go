@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// NewGetChatMessagesHandler builds a handler that returns the chat messages in a channel that a player can read
+func NewGetChatMessagesHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress, readerAddress, channel, afterSequence, isValid := parseChatReadRequest(c)
+		if !isValid {
+			return
+		}
+
+		messages, err := gameEngine.GetChatMessages(c.Request.Context(), hostAddress, readerAddress, channel, afterSequence)
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, toChatMessageResponses(messages))
+	}
+}
+
+// NewChatMessageWaitHandler builds a handler that waits for new chat messages to be posted to a channel
+func NewChatMessageWaitHandler(gameEngine game.Engine, waitTimeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress, readerAddress, channel, afterSequence, isValid := parseChatReadRequest(c)
+		if !isValid {
+			return
+		}
+
+		ctx, cancelFn := context.WithTimeout(c.Request.Context(), waitTimeout)
+		defer cancelFn()
+
+		messages, err := gameEngine.WaitForChatMessages(ctx, hostAddress, readerAddress, channel, afterSequence)
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, toChatMessageResponses(messages))
+	}
+}
+
+// NewPostChatMessageHandler builds a handler that posts a message to a chat channel
+func NewPostChatMessageHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress := c.Param("hostAddress")
+		if hostAddress == "" {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "hostAddress must be supplied")
+			return
+		}
+
+		channel, isValid := parseChatChannel(c)
+		if !isValid {
+			return
+		}
+
+		senderAddress := c.Query("playerAddress")
+		if senderAddress == "" {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "playerAddress must be supplied")
+			return
+		}
+
+		var request chatMessageRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, err.Error())
+			return
+		}
+
+		if request.Message == "" {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "message must be supplied")
+			return
+		}
+
+		message, err := gameEngine.PostChatMessage(c.Request.Context(), hostAddress, senderAddress, channel, request.Message)
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, toChatMessageResponse(message))
+	}
+}
+
+func parseChatChannel(c *gin.Context) (game.ChatChannel, bool) {
+	switch channel := game.ChatChannel(c.Param("channel")); channel {
+	case game.ChatChannelPublic, game.ChatChannelMafia:
+		return channel, true
+	default:
+		abortWithErrorResponse(c, http.StatusNotFound, errorCodeNotFound, fmt.Sprintf("unknown chat channel: '%s'", channel))
+		return "", false
+	}
+}
+
+func parseChatReadRequest(c *gin.Context) (string, string, game.ChatChannel, int, bool) {
+	hostAddress := c.Param("hostAddress")
+	if hostAddress == "" {
+		abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "hostAddress must be supplied")
+		return "", "", "", 0, false
+	}
+
+	channel, isValid := parseChatChannel(c)
+	if !isValid {
+		return "", "", "", 0, false
+	}
+
+	readerAddress := c.Query("playerAddress")
+	if readerAddress == "" {
+		abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "playerAddress must be supplied")
+		return "", "", "", 0, false
+	}
+
+	var afterSequence int
+	if afterParam := c.Query("after"); afterParam != "" {
+		parsedSequence, err := strconv.Atoi(afterParam)
+		if err != nil {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "after must be an integer")
+			return "", "", "", 0, false
+		}
+		afterSequence = parsedSequence
+	}
+
+	return hostAddress, readerAddress, channel, afterSequence, true
+}
+
+func toChatMessageResponse(message *game.ChatMessage) *chatMessageResponse {
+	return &chatMessageResponse{
+		Sequence:       message.Sequence,
+		Channel:        string(message.Channel),
+		SenderAddress:  message.SenderAddress,
+		SenderNickname: message.SenderNickname,
+		Message:        message.Message,
+		TimeOfDay:      int(message.TimeOfDay),
+		SentAt:         message.SentAt,
+	}
+}
+
+func toChatMessageResponses(messages []*game.ChatMessage) []*chatMessageResponse {
+	responses := make([]*chatMessageResponse, len(messages))
+	for messageIndex, message := range messages {
+		responses[messageIndex] = toChatMessageResponse(message)
+	}
+	return responses
+}
+
+type chatMessageRequest struct {
+	Message string `json:"message"`
+}
+
+type chatMessageResponse struct {
+	Sequence       int       `json:"sequence"`
+	Channel        string    `json:"channel"`
+	SenderAddress  string    `json:"senderAddress"`
+	SenderNickname string    `json:"senderNickname"`
+	Message        string    `json:"message"`
+	TimeOfDay      int       `json:"timeOfDay"`
+	SentAt         time.Time `json:"sentAt"`
+}
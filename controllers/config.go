@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// NewGetGameConfigHandler builds a handler that returns the configuration of a game
+func NewGetGameConfigHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress := c.Param("hostAddress")
+		if hostAddress == "" {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		config, err := gameEngine.GetGameConfig(c.Request.Context(), hostAddress)
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, toGameConfigDocument(config))
+	}
+}
+
+// parseGameConfig reads a game configuration document from the body of the given request.
+// Any settings not given in the document are left at their default values; if there is no body at all, the default configuration is returned.
+// Forced roles are rejected unless they are allowed, since they let the host choose who is in the Mafia.
+func parseGameConfig(request *http.Request, allowForcedRoles bool) (*game.GameConfig, error) {
+	document := toGameConfigDocument(game.DefaultGameConfig())
+
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	if len(bytes.TrimSpace(body)) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		// reject typos rather than silently falling back to defaults
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(document); err != nil {
+			return nil, fmt.Errorf("invalid game configuration document: %w", err)
+		}
+
+		if decoder.More() {
+			return nil, errors.New("invalid game configuration document: only a single JSON object is allowed")
+		}
+
+		if len(document.ForcedRoles) > 0 && !allowForcedRoles {
+			return nil, errors.New("invalid game configuration document: forcedRoles can only be given when the server is in dev mode")
+		}
+	}
+
+	config := document.toGameConfig()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// toGameConfigDocument builds the JSON representation of the given configuration.
+// Forced roles are left out, as they would reveal the roles of players.
+func toGameConfigDocument(config *game.GameConfig) *gameConfigDocument {
+	return &gameConfigDocument{
+		Private:              config.Private,
+		MinPlayers:           config.MinPlayers,
+		MaxPlayers:           config.MaxPlayers,
+		MafiaCount:           config.MafiaCount,
+		MafiaRatio:           config.MafiaRatio,
+		TiePolicy:            string(config.TiePolicy),
+		DayDurationSeconds:   int(config.DayDuration / time.Second),
+		NightDurationSeconds: int(config.NightDuration / time.Second),
+		StartingPhase:        int(config.StartingPhase),
+		RevealRoleOnDeath:    config.RevealRoleOnDeath,
+		AllowGodView:         config.AllowGodView,
+	}
+}
+
+// gameConfigDocument is the JSON representation of a game's configuration
+type gameConfigDocument struct {
+	Private              bool   `json:"private"`
+	MinPlayers           int    `json:"minPlayers"`
+	MaxPlayers           int    `json:"maxPlayers"`
+	MafiaCount           int    `json:"mafiaCount"`
+	MafiaRatio           int    `json:"mafiaRatio"`
+	TiePolicy            string `json:"tiePolicy"`
+	DayDurationSeconds   int    `json:"dayDurationSeconds"`
+	NightDurationSeconds int    `json:"nightDurationSeconds"`
+	StartingPhase        int    `json:"startingPhase"`
+	RevealRoleOnDeath    bool   `json:"revealRoleOnDeath"`
+	AllowGodView         bool   `json:"allowGodView"`
+	// ForcedRoles maps player addresses to the roles they must be assigned; this is only accepted in dev mode
+	ForcedRoles map[string]int `json:"forcedRoles,omitempty"`
+}
+
+func (d *gameConfigDocument) toGameConfig() *game.GameConfig {
+	var forcedRoles map[string]game.PlayerRole
+	if len(d.ForcedRoles) > 0 {
+		forcedRoles = make(map[string]game.PlayerRole, len(d.ForcedRoles))
+		for playerAddress, playerRole := range d.ForcedRoles {
+			forcedRoles[playerAddress] = game.PlayerRole(playerRole)
+		}
+	}
+
+	return &game.GameConfig{
+		Private:           d.Private,
+		MinPlayers:        d.MinPlayers,
+		MaxPlayers:        d.MaxPlayers,
+		MafiaCount:        d.MafiaCount,
+		MafiaRatio:        d.MafiaRatio,
+		TiePolicy:         game.TiePolicy(d.TiePolicy),
+		DayDuration:       time.Duration(d.DayDurationSeconds) * time.Second,
+		NightDuration:     time.Duration(d.NightDurationSeconds) * time.Second,
+		StartingPhase:     game.TimeOfDay(d.StartingPhase),
+		RevealRoleOnDeath: d.RevealRoleOnDeath,
+		AllowGodView:      d.AllowGodView,
+		ForcedRoles:       forcedRoles,
+	}
+}
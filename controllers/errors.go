@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/bots"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// errorCode identifies the kind of failure described by an error response, so that clients need not parse the message
+type errorCode string
+
+const errorCodeInvalidRequest errorCode = "invalid_request"
+const errorCodeNotFound errorCode = "not_found"
+const errorCodeConflict errorCode = "conflict"
+const errorCodeUnauthorized errorCode = "unauthorized"
+const errorCodeForbidden errorCode = "forbidden"
+const errorCodeInvalidPhase errorCode = "invalid_phase"
+const errorCodeInvalidTarget errorCode = "invalid_target"
+const errorCodeShuttingDown errorCode = "shutting_down"
+const errorCodeWaitTimeout errorCode = "wait_timeout"
+const errorCodeInternal errorCode = "internal_error"
+
+// shutdownRetryAfterSeconds is how long clients are told to wait before retrying a request rejected because the server is shutting down, which is about how long a restart takes
+const shutdownRetryAfterSeconds = "5"
+
+// errorResponse describes why a request could not be fulfilled
+type errorResponse struct {
+	Code    errorCode `json:"code,omitempty"`
+	Message string    `json:"message"`
+	// PlayersNeeded is the number of additional players that must join a game before it can be started
+	PlayersNeeded int `json:"playersNeeded,omitempty"`
+	// BotAddresses are the addresses of the bots that joined a game before adding the rest failed
+	BotAddresses []string `json:"botAddresses,omitempty"`
+}
+
+// abortWithEngineError aborts the request with the status and error code that describe the given failure of the game engine.
+// Errors that match none of the game package's sentinel errors are treated as bugs, so their messages are not exposed.
+func abortWithEngineError(c *gin.Context, err error) {
+	status, code := http.StatusInternalServerError, errorCodeInternal
+	message := err.Error()
+	switch {
+	case errors.Is(err, game.ErrInvalidRequest):
+		status, code = http.StatusBadRequest, errorCodeInvalidRequest
+	case errors.Is(err, game.ErrNotFound):
+		status, code = http.StatusNotFound, errorCodeNotFound
+	case errors.Is(err, game.ErrForbidden):
+		status, code = http.StatusForbidden, errorCodeForbidden
+	case errors.Is(err, game.ErrInvalidTarget):
+		status, code = http.StatusUnprocessableEntity, errorCodeInvalidTarget
+	case errors.Is(err, game.ErrInvalidPhase):
+		status, code = http.StatusConflict, errorCodeInvalidPhase
+	case errors.Is(err, game.ErrConflict):
+		status, code = http.StatusConflict, errorCodeConflict
+	case errors.Is(err, game.ErrShuttingDown):
+		status, code = http.StatusServiceUnavailable, errorCodeShuttingDown
+		c.Header("Retry-After", shutdownRetryAfterSeconds)
+	case errors.Is(err, context.DeadlineExceeded):
+		// only long polls have a deadline, so this is a wait that reached the wait timeout with nothing to report
+		status, code = http.StatusRequestTimeout, errorCodeWaitTimeout
+		message = "nothing happened before the wait timed out; wait again to keep waiting"
+	}
+
+	// keep the error attached to the request so that it is still logged
+	_ = c.Error(err)
+
+	response := &errorResponse{
+		Code:    code,
+		Message: message,
+	}
+	if code == errorCodeInternal {
+		response.Message = http.StatusText(status)
+	}
+
+	var notEnoughPlayersErr *game.NotEnoughPlayersError
+	if errors.As(err, &notEnoughPlayersErr) {
+		response.PlayersNeeded = notEnoughPlayersErr.PlayersNeeded()
+	}
+
+	var botJoinErr *bots.JoinError
+	if errors.As(err, &botJoinErr) {
+		response.BotAddresses = botJoinErr.BotAddresses
+	}
+
+	c.AbortWithStatusJSON(status, response)
+}
+
+// abortWithErrorResponse aborts the request with the given status and a body describing why the request could not be fulfilled
+func abortWithErrorResponse(c *gin.Context, status int, code errorCode, message string) {
+	c.AbortWithStatusJSON(status, &errorResponse{
+		Code:    code,
+		Message: message,
+	})
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/faults"
+)
+
+// NewClearFaultsHandler builds a handler that stops injecting faults into requests
+func NewClearFaultsHandler(injector *faults.Injector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := injector.SetConfig(&faults.Config{}); err != nil {
+			abortWithErrorResponse(c, http.StatusInternalServerError, errorCodeInternal, err.Error())
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// NewGetFaultsHandler builds a handler that returns the faults currently being injected into requests
+func NewGetFaultsHandler(injector *faults.Injector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, injector.GetConfig())
+	}
+}
+
+// NewSetFaultsHandler builds a handler that replaces the faults injected into requests with those described in the request body
+func NewSetFaultsHandler(injector *faults.Injector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var config faults.Config
+		decoder := json.NewDecoder(c.Request.Body)
+		// reject typos rather than silently injecting nothing
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&config); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, &errorResponse{
+				Code:    errorCodeInvalidRequest,
+				Message: "invalid fault configuration: " + err.Error(),
+			})
+			return
+		}
+
+		if err := injector.SetConfig(&config); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, &errorResponse{
+				Code:    errorCodeInvalidRequest,
+				Message: "invalid fault configuration: " + err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, &config)
+	}
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// NewFinishGameHandler builds a handler for handling the finishing of games that have been played to completion
+func NewFinishGameHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress := c.Param("hostAddress")
+		if hostAddress == "" {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		if err := gameEngine.FinishGame(c.Request.Context(), hostAddress); err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
@@ -7,8 +7,9 @@ import (
 	"github.com/jrh3k5/mafia-dapp-http/game"
 )
 
-// NewInitializeGameHandler creates a new handler to initialize a game
-func NewInitializeGameHandler(gameEngine game.Engine) gin.HandlerFunc {
+// NewInitializeGameHandler creates a new handler to initialize a game, optionally configured by a game configuration document in the request body.
+// Forced roles are only accepted in the document if allowForcedRoles is set.
+func NewInitializeGameHandler(gameEngine game.Engine, allowForcedRoles bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		hostAddress := c.Param("hostAddress")
 		if hostAddress == "" {
@@ -16,8 +17,18 @@ func NewInitializeGameHandler(gameEngine game.Engine) gin.HandlerFunc {
 			return
 		}
 
-		if err := gameEngine.InitializeGame(c.Request.Context(), hostAddress); err != nil {
-			_ = c.AbortWithError(http.StatusInternalServerError, err)
+		config, err := parseGameConfig(c.Request, allowForcedRoles)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, &errorResponse{
+				Code:    errorCodeInvalidRequest,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		if err := gameEngine.InitializeGame(c.Request.Context(), hostAddress, config); err != nil {
+			abortWithEngineError(c, err)
+			return
 		}
 
 		c.Status(http.StatusOK)
@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// NewLeaveGameHandler builds a handler that removes a player from a game, either at the player's request or, before the game starts, the host's
+func NewLeaveGameHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress := c.Param("hostAddress")
+		if hostAddress == "" {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		playerAddress := c.Param("playerAddress")
+		if playerAddress == "" {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		// if no requester is given, then the player is assumed to be leaving of their own accord
+		requesterAddress := c.Query("requesterAddress")
+		if requesterAddress == "" {
+			requesterAddress = playerAddress
+		}
+
+		if err := gameEngine.LeaveGame(c.Request.Context(), hostAddress, requesterAddress, playerAddress); err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// NewGetLobbyHandler builds a handler that lists the public games that can be joined
+func NewGetLobbyHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lobby, err := gameEngine.GetLobby(c.Request.Context())
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, toLobbyResponse(lobby))
+	}
+}
+
+// NewLobbyWaitHandler builds a handler that waits for the listing of joinable games to change from the given version
+func NewLobbyWaitHandler(gameEngine game.Engine, waitTimeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sinceVersion, err := strconv.Atoi(c.Query("version"))
+		if err != nil {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "version must be supplied as an integer")
+			return
+		}
+
+		ctx, cancelFn := context.WithTimeout(c.Request.Context(), waitTimeout)
+		defer cancelFn()
+
+		lobby, err := gameEngine.WaitForLobbyUpdate(ctx, sinceVersion)
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, toLobbyResponse(lobby))
+	}
+}
+
+func toLobbyResponse(lobby *game.Lobby) *lobbyResponse {
+	games := make([]*lobbyGameResponse, len(lobby.Games))
+	for gameIndex, lobbyGame := range lobby.Games {
+		games[gameIndex] = &lobbyGameResponse{
+			HostAddress:  lobbyGame.HostAddress,
+			HostNickname: lobbyGame.HostNickname,
+			PlayerCount:  lobbyGame.PlayerCount,
+			MinPlayers:   lobbyGame.MinPlayers,
+			Capacity:     lobbyGame.Capacity,
+			CreatedAt:    lobbyGame.CreatedAt,
+		}
+	}
+
+	return &lobbyResponse{
+		Version: lobby.Version,
+		Games:   games,
+	}
+}
+
+type lobbyResponse struct {
+	Version int                  `json:"version"`
+	Games   []*lobbyGameResponse `json:"games"`
+}
+
+type lobbyGameResponse struct {
+	HostAddress  string    `json:"hostAddress"`
+	HostNickname string    `json:"hostNickname"`
+	PlayerCount  int       `json:"playerCount"`
+	MinPlayers   int       `json:"minPlayers"`
+	Capacity     int       `json:"capacity"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
@@ -0,0 +1,11 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/metrics"
+)
+
+// NewMetricsHandler builds a handler that serves the metrics of the server to Prometheus
+func NewMetricsHandler(serverMetrics *metrics.Metrics) gin.HandlerFunc {
+	return gin.WrapH(serverMetrics.Handler())
+}
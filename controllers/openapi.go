@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/openapi"
+)
+
+// NewDocsHandler builds a handler that serves a page for browsing the OpenAPI document
+func NewDocsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.DocsPage())
+	}
+}
+
+// NewOpenAPIHandler builds a handler that serves the OpenAPI document describing every route
+func NewOpenAPIHandler(document *openapi.Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", document.JSON())
+	}
+}
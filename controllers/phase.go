@@ -17,16 +17,17 @@ func NewPhaseExecutionHandler(gameEngine game.Engine) gin.HandlerFunc {
 			return
 		}
 
-		if err := gameEngine.ExecutePhase(c.Request.Context(), hostAddress); err != nil {
-			_ = c.AbortWithError(http.StatusInternalServerError, err)
+		phaseExecution, err := gameEngine.ExecutePhase(c.Request.Context(), hostAddress)
+		if err != nil {
+			abortWithEngineError(c, err)
 			return
 		}
 
-		c.Status(http.StatusOK)
+		c.JSON(http.StatusOK, toPhaseExecutionResponse(phaseExecution))
 	}
 }
 
-func NewPhaseExecutionWaitHandler(gameEngine game.Engine) gin.HandlerFunc {
+func NewPhaseExecutionWaitHandler(gameEngine game.Engine, waitTimeout time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		hostAddress := c.Param("hostAddress")
 		if hostAddress == "" {
@@ -34,22 +35,27 @@ func NewPhaseExecutionWaitHandler(gameEngine game.Engine) gin.HandlerFunc {
 			return
 		}
 
-		ctx, cancelFn := context.WithTimeout(c.Request.Context(), 10*time.Minute)
+		ctx, cancelFn := context.WithTimeout(c.Request.Context(), waitTimeout)
 		defer cancelFn()
 
 		phaseExecution, err := gameEngine.WaitForPhaseExecution(ctx, hostAddress)
 		if err != nil {
-			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			abortWithEngineError(c, err)
 			return
 		}
 
-		c.JSON(http.StatusOK, &phaseExecutionResponse{
-			HostAddress:      phaseExecution.HostAddress,
-			PhaseOutcome:     int(phaseExecution.PhaseOutcome),
-			CurrentPhase:     int(phaseExecution.CurrentPhase),
-			KilledPlayers:    phaseExecution.KilledPlayers,
-			ConvictedPlayers: phaseExecution.ConvictedPlayers,
-		})
+		c.JSON(http.StatusOK, toPhaseExecutionResponse(phaseExecution))
+	}
+}
+
+func toPhaseExecutionResponse(phaseExecution *game.PhaseExecution) *phaseExecutionResponse {
+	return &phaseExecutionResponse{
+		HostAddress:      phaseExecution.HostAddress,
+		PhaseOutcome:     int(phaseExecution.PhaseOutcome),
+		CurrentPhase:     int(phaseExecution.CurrentPhase),
+		KilledPlayers:    phaseExecution.KilledPlayers,
+		ConvictedPlayers: phaseExecution.ConvictedPlayers,
+		ForfeitedPlayers: phaseExecution.ForfeitedPlayers,
 	}
 }
 
@@ -59,4 +65,5 @@ type phaseExecutionResponse struct {
 	CurrentPhase     int      `json:"currentPhase"`
 	KilledPlayers    []string `json:"killedPlayers"`
 	ConvictedPlayers []string `json:"convictedPlayers"`
+	ForfeitedPlayers []string `json:"forfeitedPlayers,omitempty"`
 }
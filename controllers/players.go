@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -24,12 +25,12 @@ func NewGetPlayerHandler(gameEngine game.Engine) gin.HandlerFunc {
 
 		player, err := gameEngine.GetPlayer(c.Request.Context(), hostAddress, playerAddress)
 		if err != nil {
-			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			abortWithEngineError(c, err)
 			return
 		}
 
 		if player == nil {
-			c.AbortWithStatus(http.StatusNotFound)
+			abortWithErrorResponse(c, http.StatusNotFound, errorCodeNotFound, fmt.Sprintf("player '%s' is not a member of the game", playerAddress))
 			return
 		}
 
@@ -38,6 +39,9 @@ func NewGetPlayerHandler(gameEngine game.Engine) gin.HandlerFunc {
 			PlayerAddress:  player.PlayerAddress,
 			PlayerNickname: player.PlayerNickname,
 			PlayerRole:     &playerRoleInt,
+			Dead:           &player.Dead,
+			Convicted:      &player.Convicted,
+			Forfeited:      &player.Forfeited,
 		})
 	}
 }
@@ -53,25 +57,104 @@ func NewGetPlayersHandler(gameEngine game.Engine) gin.HandlerFunc {
 
 		players, err := gameEngine.GetPlayers(c.Request.Context(), hostAddress)
 		if err != nil {
-			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			abortWithEngineError(c, err)
 			return
 		}
 
-		returnedPlayers := make([]*playerResponse, len(players))
-		for playerIndex, player := range players {
-			returnedPlayers[playerIndex] = &playerResponse{
-				PlayerAddress:  player.PlayerAddress,
-				PlayerNickname: player.PlayerNickname,
-				// deliberately leave out the player role to not leak information
+		config, err := gameEngine.GetGameConfig(c.Request.Context(), hostAddress)
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, toPlayerListResponse(players, config, false))
+	}
+}
+
+// NewGetMafiaTeammatesHandler builds a handler that lets a member of the Mafia see the other members of the Mafia
+func NewGetMafiaTeammatesHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress := c.Param("hostAddress")
+		if hostAddress == "" {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		playerAddress := c.Param("playerAddress")
+		if playerAddress == "" {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		player, err := gameEngine.GetPlayer(c.Request.Context(), hostAddress, playerAddress)
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		if player == nil {
+			abortWithErrorResponse(c, http.StatusNotFound, errorCodeNotFound, fmt.Sprintf("player '%s' is not a member of the game", playerAddress))
+			return
+		}
+
+		if player.PlayerRole != game.PlayerRoleMafia {
+			// only the Mafia know who is in the Mafia
+			abortWithErrorResponse(c, http.StatusForbidden, errorCodeForbidden, "only members of the Mafia can see the members of the Mafia")
+			return
+		}
+
+		players, err := gameEngine.GetPlayers(c.Request.Context(), hostAddress)
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		teammates := make([]*playerResponse, 0)
+		for _, teammate := range players {
+			if teammate.PlayerRole != game.PlayerRoleMafia || teammate.PlayerAddress == player.PlayerAddress {
+				continue
 			}
+
+			teammates = append(teammates, &playerResponse{
+				PlayerAddress:  teammate.PlayerAddress,
+				PlayerNickname: teammate.PlayerNickname,
+			})
+		}
+
+		c.JSON(http.StatusOK, teammates)
+	}
+}
+
+// toPlayerListResponse builds the listing of the given players.
+// Roles are only included if revealRoles is true or if the game reveals the roles of players who can no longer act.
+func toPlayerListResponse(players []*game.Player, config *game.GameConfig, revealRoles bool) []*playerResponse {
+	returnedPlayers := make([]*playerResponse, len(players))
+	for playerIndex, player := range players {
+		dead := player.Dead
+		convicted := player.Convicted
+		returnedPlayer := &playerResponse{
+			PlayerAddress:  player.PlayerAddress,
+			PlayerNickname: player.PlayerNickname,
+			Dead:           &dead,
+			Convicted:      &convicted,
+			// deliberately leave out the player role to not leak information
+		}
+
+		if revealRoles || (config.RevealRoleOnDeath && !player.CanAct()) {
+			playerRoleInt := int(player.PlayerRole)
+			returnedPlayer.PlayerRole = &playerRoleInt
 		}
 
-		c.JSON(http.StatusOK, returnedPlayers)
+		returnedPlayers[playerIndex] = returnedPlayer
 	}
+	return returnedPlayers
 }
 
 type playerResponse struct {
 	PlayerAddress  string `json:"playerAddress"`
 	PlayerNickname string `json:"playerNickname"`
 	PlayerRole     *int   `json:"playerRole,omitempty"`
+	Dead           *bool  `json:"dead,omitempty"`
+	Convicted      *bool  `json:"convicted,omitempty"`
+	Forfeited      *bool  `json:"forfeited,omitempty"`
 }
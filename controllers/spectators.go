@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// NewAddSpectatorHandler builds a handler that registers a non-player as a spectator of a game
+func NewAddSpectatorHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress := c.Param("hostAddress")
+		if hostAddress == "" {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "hostAddress must be supplied")
+			return
+		}
+
+		spectatorAddress := c.Query("spectatorAddress")
+		if spectatorAddress == "" {
+			abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "spectatorAddress must be supplied")
+			return
+		}
+
+		var godView bool
+		if godViewParam := c.Query("godView"); godViewParam != "" {
+			parsedGodView, err := strconv.ParseBool(godViewParam)
+			if err != nil {
+				abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "godView must be a boolean")
+				return
+			}
+			godView = parsedGodView
+		}
+
+		if err := gameEngine.AddSpectator(c.Request.Context(), hostAddress, spectatorAddress, godView); err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// NewGetSpectatorHandler builds a handler that describes how a spectator is watching a game
+func NewGetSpectatorHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, spectator, isValid := getSpectator(c, gameEngine)
+		if !isValid {
+			return
+		}
+
+		c.JSON(http.StatusOK, &spectatorResponse{
+			SpectatorAddress: spectator.SpectatorAddress,
+			GodView:          spectator.GodView,
+		})
+	}
+}
+
+// NewGetSpectatorPlayersHandler builds a handler that lists the players of a game for a spectator.
+// Spectators with the god view can see the roles of all players.
+func NewGetSpectatorPlayersHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress, spectator, isValid := getSpectator(c, gameEngine)
+		if !isValid {
+			return
+		}
+
+		players, err := gameEngine.GetPlayers(c.Request.Context(), hostAddress)
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		config, err := gameEngine.GetGameConfig(c.Request.Context(), hostAddress)
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, toPlayerListResponse(players, config, spectator.GodView))
+	}
+}
+
+// NewGetSpectatorVotesHandler builds a handler that shows the votes cast so far in the current phase to spectators with the god view
+func NewGetSpectatorVotesHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostAddress, spectator, isValid := getSpectator(c, gameEngine)
+		if !isValid {
+			return
+		}
+
+		if !spectator.GodView {
+			abortWithErrorResponse(c, http.StatusForbidden, errorCodeForbidden, "only spectators with god view can see the votes")
+			return
+		}
+
+		votes, err := gameEngine.GetVotes(c.Request.Context(), hostAddress)
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &votesResponse{
+			MafiaAccusations: votes.MafiaAccusations,
+			KillVotes:        votes.KillVotes,
+		})
+	}
+}
+
+func getSpectator(c *gin.Context, gameEngine game.Engine) (string, *game.Spectator, bool) {
+	hostAddress := c.Param("hostAddress")
+	if hostAddress == "" {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return "", nil, false
+	}
+
+	spectatorAddress := c.Param("spectatorAddress")
+	if spectatorAddress == "" {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return "", nil, false
+	}
+
+	spectator, err := gameEngine.GetSpectator(c.Request.Context(), hostAddress, spectatorAddress)
+	if err != nil {
+		abortWithEngineError(c, err)
+		return "", nil, false
+	}
+
+	if spectator == nil {
+		abortWithErrorResponse(c, http.StatusNotFound, errorCodeNotFound, fmt.Sprintf("'%s' is not spectating the game", spectatorAddress))
+		return "", nil, false
+	}
+
+	return hostAddress, spectator, true
+}
+
+type spectatorResponse struct {
+	SpectatorAddress string `json:"spectatorAddress"`
+	GodView          bool   `json:"godView"`
+}
+
+type votesResponse struct {
+	MafiaAccusations map[string]string `json:"mafiaAccusations"`
+	KillVotes        map[string]string `json:"killVotes"`
+}
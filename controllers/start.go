@@ -19,7 +19,7 @@ func NewStartGameHandler(gameEngine game.Engine) gin.HandlerFunc {
 		}
 
 		if err := gameEngine.StartGame(c.Request.Context(), hostAddress); err != nil {
-			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			abortWithEngineError(c, err)
 			return
 		}
 
@@ -28,7 +28,7 @@ func NewStartGameHandler(gameEngine game.Engine) gin.HandlerFunc {
 }
 
 // NewGameStartWaitHandler builds a handler to handle the waiting for a game start
-func NewGameStartWaitHandler(gameEngine game.Engine) gin.HandlerFunc {
+func NewGameStartWaitHandler(gameEngine game.Engine, waitTimeout time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		hostAddress := c.Param("hostAddress")
 		if hostAddress == "" {
@@ -36,11 +36,11 @@ func NewGameStartWaitHandler(gameEngine game.Engine) gin.HandlerFunc {
 			return
 		}
 
-		ctx, cancelFn := context.WithTimeout(c.Request.Context(), 10*time.Minute)
+		ctx, cancelFn := context.WithTimeout(c.Request.Context(), waitTimeout)
 		defer cancelFn()
 
 		if err := gameEngine.WaitForGameStart(ctx, hostAddress); err != nil {
-			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			abortWithEngineError(c, err)
 			return
 		}
 
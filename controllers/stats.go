@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// NewGetPlayerStatsHandler builds a handler that returns the statistics of a player across all completed games
+func NewGetPlayerStatsHandler(gameEngine game.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		playerAddress := c.Param("playerAddress")
+		if playerAddress == "" {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		stats, err := gameEngine.GetPlayerStats(c.Request.Context(), playerAddress)
+		if err != nil {
+			abortWithEngineError(c, err)
+			return
+		}
+
+		var accusationAccuracy float64
+		if stats.Accusations > 0 {
+			accusationAccuracy = float64(stats.CorrectAccusations) / float64(stats.Accusations)
+		}
+
+		c.JSON(http.StatusOK, &playerStatsResponse{
+			PlayerAddress:      stats.PlayerAddress,
+			GamesPlayed:        stats.GamesPlayed,
+			CivilianWins:       stats.CivilianWins,
+			MafiaWins:          stats.MafiaWins,
+			TimesConvicted:     stats.TimesConvicted,
+			TimesKilled:        stats.TimesKilled,
+			Accusations:        stats.Accusations,
+			CorrectAccusations: stats.CorrectAccusations,
+			AccusationAccuracy: accusationAccuracy,
+		})
+	}
+}
+
+type playerStatsResponse struct {
+	PlayerAddress      string  `json:"playerAddress"`
+	GamesPlayed        int     `json:"gamesPlayed"`
+	CivilianWins       int     `json:"civilianWins"`
+	MafiaWins          int     `json:"mafiaWins"`
+	TimesConvicted     int     `json:"timesConvicted"`
+	TimesKilled        int     `json:"timesKilled"`
+	Accusations        int     `json:"accusations"`
+	CorrectAccusations int     `json:"correctAccusations"`
+	AccusationAccuracy float64 `json:"accusationAccuracy"`
+}
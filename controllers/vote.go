@@ -1,10 +1,9 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
 
-	"errors"
-
 	"github.com/gin-gonic/gin"
 	"github.com/jrh3k5/mafia-dapp-http/game"
 )
@@ -18,7 +17,7 @@ func NewPlayerVoteHandler(gameEngine game.Engine) gin.HandlerFunc {
 		case "kill":
 			handleKillVote(c, gameEngine)
 		default:
-			c.AbortWithStatus(http.StatusNotFound)
+			abortWithErrorResponse(c, http.StatusNotFound, errorCodeNotFound, fmt.Sprintf("unknown vote action: '%s'", action))
 		}
 	}
 }
@@ -26,24 +25,24 @@ func NewPlayerVoteHandler(gameEngine game.Engine) gin.HandlerFunc {
 func handleAccusation(c *gin.Context, gameEngine game.Engine) {
 	hostAddress := c.Param("hostAddress")
 	if hostAddress == "" {
-		c.AbortWithError(http.StatusBadRequest, errors.New("host address must be supplidd"))
+		abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "host address must be supplidd")
 		return
 	}
 
 	voterAddress := c.Param("voterAddress")
 	if voterAddress == "" {
-		c.AbortWithError(http.StatusBadRequest, errors.New("voter address must be supplied"))
+		abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "voter address must be supplied")
 		return
 	}
 
 	accuseeAddress := c.Query("playerAddress")
 	if accuseeAddress == "" {
-		c.AbortWithError(http.StatusBadRequest, errors.New("vote receipient must be supplied"))
+		abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "vote receipient must be supplied")
 		return
 	}
 
 	if err := gameEngine.AccuseAsMafia(c.Request.Context(), hostAddress, voterAddress, accuseeAddress); err != nil {
-		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		abortWithEngineError(c, err)
 		return
 	}
 
@@ -53,24 +52,24 @@ func handleAccusation(c *gin.Context, gameEngine game.Engine) {
 func handleKillVote(c *gin.Context, gameEngine game.Engine) {
 	hostAddress := c.Param("hostAddress")
 	if hostAddress == "" {
-		c.AbortWithError(http.StatusBadRequest, errors.New("hostAddress must be supplied"))
+		abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "hostAddress must be supplied")
 		return
 	}
 
 	killerAddress := c.Param("voterAddress")
 	if killerAddress == "" {
-		c.AbortWithError(http.StatusBadRequest, errors.New("voterAddress must be supplied"))
+		abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "voterAddress must be supplied")
 		return
 	}
 
 	victimAddress := c.Query("playerAddress")
 	if victimAddress == "" {
-		c.AbortWithError(http.StatusBadRequest, errors.New("playerAddress must be supplied"))
+		abortWithErrorResponse(c, http.StatusBadRequest, errorCodeInvalidRequest, "playerAddress must be supplied")
 		return
 	}
 
 	if err := gameEngine.VoteToKill(c.Request.Context(), hostAddress, killerAddress, victimAddress); err != nil {
-		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		abortWithEngineError(c, err)
 		return
 	}
 
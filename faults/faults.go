@@ -0,0 +1,152 @@
+// Package faults simulates the slow confirmations and failed transactions of the real dapp by delaying, failing, and dropping requests.
+package faults
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// Config describes the faults injected into requests
+type Config struct {
+	// Global applies to every request whose route has no rule of its own
+	Global *Rule `json:"global,omitempty"`
+	// Routes are rules keyed by either a route pattern (e.g., "/game/:hostAddress/join") or a method and a route pattern (e.g., "POST /game/:hostAddress/join").
+	// A rule keyed by method takes precedence over one keyed only by route, and either replaces the global rule entirely.
+	Routes map[string]*Rule `json:"routes,omitempty"`
+}
+
+// Rule describes the faults injected into the requests to which it applies
+type Rule struct {
+	Latency *Latency `json:"latency,omitempty"`
+	// FailureRate is the probability, from 0 to 1, that a request fails with a server error instead of being handled
+	FailureRate float64 `json:"failureRate"`
+	// FailureStatuses are the statuses from which the status of a failed request is chosen at random; if empty, 503 is used
+	FailureStatuses []int `json:"failureStatuses,omitempty"`
+	// DropRate is the probability, from 0 to 1, that the connection is closed without any response, as if a long poll was dropped
+	DropRate float64 `json:"dropRate"`
+}
+
+// LatencyDistribution is the shape of the distribution from which a delay is chosen
+type LatencyDistribution string
+
+// LatencyDistributionFixed delays every request by the mean
+const LatencyDistributionFixed LatencyDistribution = "fixed"
+
+// LatencyDistributionUniform delays requests by anywhere from the minimum to the maximum
+const LatencyDistributionUniform LatencyDistribution = "uniform"
+
+// LatencyDistributionNormal delays requests around the mean, by the standard deviation, within the minimum and maximum
+const LatencyDistributionNormal LatencyDistribution = "normal"
+
+// LatencyDistributionExponential mostly delays requests by a little, but occasionally by a lot, averaging the mean, within the minimum and maximum
+const LatencyDistributionExponential LatencyDistribution = "exponential"
+
+// Latency describes how long requests are delayed before they are handled
+type Latency struct {
+	Distribution LatencyDistribution `json:"distribution"`
+	MinMs        int                 `json:"minMs"`
+	// MaxMs bounds the delay; zero means that the delay is unbounded, except for the uniform distribution, which requires it
+	MaxMs    int `json:"maxMs"`
+	MeanMs   int `json:"meanMs"`
+	StdDevMs int `json:"stdDevMs"`
+}
+
+// Validate determines whether the configuration can be applied
+func (c *Config) Validate() error {
+	if c.Global != nil {
+		if err := c.Global.Validate(); err != nil {
+			return fmt.Errorf("invalid global rule: %w", err)
+		}
+	}
+
+	for routeKey, rule := range c.Routes {
+		if rule == nil {
+			return fmt.Errorf("the rule for '%s' cannot be null", routeKey)
+		}
+
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("invalid rule for '%s': %w", routeKey, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate determines whether the rule can be applied
+func (r *Rule) Validate() error {
+	if r.FailureRate < 0 || r.FailureRate > 1 {
+		return fmt.Errorf("the failure rate must be from 0 to 1, not %v", r.FailureRate)
+	} else if r.DropRate < 0 || r.DropRate > 1 {
+		return fmt.Errorf("the drop rate must be from 0 to 1, not %v", r.DropRate)
+	}
+
+	for _, failureStatus := range r.FailureStatuses {
+		if failureStatus < 500 || failureStatus > 599 {
+			return fmt.Errorf("failure statuses must be server errors, not %d", failureStatus)
+		}
+	}
+
+	if r.Latency != nil {
+		if err := r.Latency.Validate(); err != nil {
+			return fmt.Errorf("invalid latency: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Validate determines whether delays can be chosen from the latency
+func (l *Latency) Validate() error {
+	if l.MinMs < 0 || l.MaxMs < 0 || l.MeanMs < 0 || l.StdDevMs < 0 {
+		return errors.New("latency durations cannot be negative")
+	} else if l.MaxMs > 0 && l.MaxMs < l.MinMs {
+		return fmt.Errorf("the maximum latency (%dms) cannot be less than the minimum latency (%dms)", l.MaxMs, l.MinMs)
+	}
+
+	switch l.Distribution {
+	case LatencyDistributionFixed, LatencyDistributionNormal, LatencyDistributionExponential:
+	case LatencyDistributionUniform:
+		if l.MaxMs == 0 {
+			return errors.New("a uniform latency requires a maximum")
+		}
+	default:
+		return fmt.Errorf("unknown latency distribution: '%s'", l.Distribution)
+	}
+
+	return nil
+}
+
+// chooseDelay chooses how long to delay a request
+func (l *Latency) chooseDelay() time.Duration {
+	var delayMs float64
+	switch l.Distribution {
+	case LatencyDistributionFixed:
+		return time.Duration(l.MeanMs) * time.Millisecond
+	case LatencyDistributionUniform:
+		delayMs = float64(l.MinMs) + rand.Float64()*float64(l.MaxMs-l.MinMs)
+	case LatencyDistributionNormal:
+		delayMs = float64(l.MeanMs) + rand.NormFloat64()*float64(l.StdDevMs)
+	case LatencyDistributionExponential:
+		delayMs = rand.ExpFloat64() * float64(l.MeanMs)
+	}
+
+	delayMs = math.Max(delayMs, float64(l.MinMs))
+	if l.MaxMs > 0 {
+		delayMs = math.Min(delayMs, float64(l.MaxMs))
+	}
+
+	return time.Duration(delayMs * float64(time.Millisecond))
+}
+
+// chooseFailureStatus chooses the status with which to fail a request
+func (r *Rule) chooseFailureStatus() int {
+	if len(r.FailureStatuses) == 0 {
+		return http.StatusServiceUnavailable
+	}
+
+	return r.FailureStatuses[rand.Intn(len(r.FailureStatuses))]
+}
@@ -0,0 +1,132 @@
+package faults
+
+import (
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Injector injects faults into requests according to a configuration that can be changed at any time
+type Injector struct {
+	logger      *slog.Logger
+	config      *Config
+	configMutex sync.RWMutex
+	// excludedPrefixes are the path prefixes of routes into which faults are never injected
+	excludedPrefixes []string
+}
+
+// NewInjector builds an injector that injects no faults until it is configured.
+// Faults are never injected into routes starting with any of the given prefixes, so that the routes used to configure the injector keep working.
+func NewInjector(logger *slog.Logger, excludedPrefixes ...string) *Injector {
+	return &Injector{
+		logger:           logger,
+		config:           &Config{},
+		excludedPrefixes: excludedPrefixes,
+	}
+}
+
+// GetConfig gets the current configuration
+func (i *Injector) GetConfig() *Config {
+	i.configMutex.RLock()
+	defer i.configMutex.RUnlock()
+
+	return i.config
+}
+
+// SetConfig replaces the current configuration
+func (i *Injector) SetConfig(config *Config) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	i.configMutex.Lock()
+	defer i.configMutex.Unlock()
+
+	i.config = config
+
+	return nil
+}
+
+// Middleware builds a handler that injects faults into requests before they are handled
+func (i *Injector) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// preflight requests are made by browsers rather than the dapp, so they are left alone
+		routePath := c.FullPath()
+		if routePath == "" || c.Request.Method == http.MethodOptions || i.isExcluded(routePath) {
+			c.Next()
+			return
+		}
+
+		rule := i.getRule(c.Request.Method, routePath)
+		if rule == nil {
+			c.Next()
+			return
+		}
+
+		if rule.Latency != nil {
+			select {
+			case <-time.After(rule.Latency.chooseDelay()):
+			case <-c.Request.Context().Done():
+				c.Abort()
+				return
+			}
+		}
+
+		if rule.DropRate > 0 && rand.Float64() < rule.DropRate {
+			i.drop(c)
+			return
+		}
+
+		if rule.FailureRate > 0 && rand.Float64() < rule.FailureRate {
+			c.AbortWithStatusJSON(rule.chooseFailureStatus(), gin.H{"message": "injected failure"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// drop closes the connection of the given request without responding
+func (i *Injector) drop(c *gin.Context) {
+	c.Abort()
+
+	connection, _, err := c.Writer.Hijack()
+	if err != nil {
+		i.logger.ErrorContext(c.Request.Context(), "failed to drop connection", "path", c.Request.URL.Path, "error", err)
+		return
+	}
+
+	if err := connection.Close(); err != nil {
+		i.logger.ErrorContext(c.Request.Context(), "failed to close dropped connection", "path", c.Request.URL.Path, "error", err)
+	}
+}
+
+// getRule gets the rule that applies to the given route, if any
+func (i *Injector) getRule(method string, routePath string) *Rule {
+	i.configMutex.RLock()
+	defer i.configMutex.RUnlock()
+
+	if rule, hasRule := i.config.Routes[method+" "+routePath]; hasRule {
+		return rule
+	}
+
+	if rule, hasRule := i.config.Routes[routePath]; hasRule {
+		return rule
+	}
+
+	return i.config.Global
+}
+
+func (i *Injector) isExcluded(routePath string) bool {
+	for _, excludedPrefix := range i.excludedPrefixes {
+		if strings.HasPrefix(routePath, excludedPrefix) {
+			return true
+		}
+	}
+	return false
+}
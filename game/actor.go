@@ -0,0 +1,123 @@
+package game
+
+import (
+	"context"
+	"sync"
+)
+
+// gameActor runs a single game on a goroutine of its own.
+// Every command against the game is run by that goroutine, one at a time and in the order in which they were sent, so every change to the game is atomic and the game needs no locks.
+type gameActor struct {
+	hostAddress string
+	state       *gameState
+	commands    chan func(*gameState)
+	// stopped is closed once the game has ended, after which no more commands are run
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+// newGameActor starts running the given game
+func newGameActor(hostAddress string, state *gameState) *gameActor {
+	actor := &gameActor{
+		hostAddress: hostAddress,
+		state:       state,
+		commands:    make(chan func(*gameState)),
+		stopped:     make(chan struct{}),
+	}
+
+	go actor.run()
+
+	return actor
+}
+
+func (a *gameActor) run() {
+	for {
+		select {
+		case command := <-a.commands:
+			command(a.state)
+		case <-a.stopped:
+			return
+		}
+	}
+}
+
+// stop stops running the game once the command being run, if any, has finished; stopping more than once does nothing
+func (a *gameActor) stop() {
+	a.stopOnce.Do(func() {
+		close(a.stopped)
+	})
+}
+
+// runCommand runs the given command against the game of the given actor and waits for its result.
+// This fails with an error matching ErrNotFound if the game has ended before the command could be run.
+// A command must never run another command against its own game, since the game's goroutine is busy running the first one.
+func runCommand[T any](ctx context.Context, actor *gameActor, command func(g *gameState) (T, error)) (T, error) {
+	type commandResult struct {
+		value T
+		err   error
+	}
+
+	// buffered so that the game's goroutine never waits for the result to be read
+	results := make(chan commandResult, 1)
+	runnableCommand := func(g *gameState) {
+		value, err := command(g)
+		results <- commandResult{
+			value: value,
+			err:   err,
+		}
+	}
+
+	select {
+	case actor.commands <- runnableCommand:
+	case <-actor.stopped:
+		var zero T
+		return zero, newGameNotFoundError(actor.hostAddress)
+	case <-ctx.Done():
+		var zero T
+		return zero, context.Cause(ctx)
+	}
+
+	// once the game's goroutine has taken the command, it runs the command to completion
+	result := <-results
+	return result.value, result.err
+}
+
+// runGameCommand runs the given command against the game for the given host address and waits for its result.
+// This fails with an error matching ErrNotFound if there is no such game.
+func runGameCommand[T any](ctx context.Context, i *InMemoryEngine, hostAddress string, command func(g *gameState) (T, error)) (T, error) {
+	actor, hasActor := i.getGameActor(hostAddress)
+	if !hasActor {
+		var zero T
+		return zero, newGameNotFoundError(hostAddress)
+	}
+
+	return runCommand(ctx, actor, command)
+}
+
+// runGameAction runs the given command, which has no result beyond whether it failed, against the game for the given host address
+func (i *InMemoryEngine) runGameAction(ctx context.Context, hostAddress string, command func(g *gameState) error) error {
+	_, err := runGameCommand(ctx, i, hostAddress, func(g *gameState) (struct{}, error) {
+		return struct{}{}, command(g)
+	})
+	return err
+}
+
+func (i *InMemoryEngine) getGameActor(hostAddress string) (*gameActor, bool) {
+	i.gameActorsMutex.RLock()
+	defer i.gameActorsMutex.RUnlock()
+
+	actor, hasActor := i.gameActors[hostAddress]
+	return actor, hasActor
+}
+
+// getGameActors gets the actors of every game that has not ended
+func (i *InMemoryEngine) getGameActors() []*gameActor {
+	i.gameActorsMutex.RLock()
+	defer i.gameActorsMutex.RUnlock()
+
+	actors := make([]*gameActor, 0, len(i.gameActors))
+	for _, actor := range i.gameActors {
+		actors = append(actors, actor)
+	}
+	return actors
+}
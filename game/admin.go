@@ -0,0 +1,94 @@
+package game
+
+import "context"
+
+// AdminEngine forces games into particular states, bypassing the rules of the game.
+// This is only meant to be used during development, such as to jump a UI straight to an interesting state.
+type AdminEngine interface {
+	// ClearVotes discards all of the Mafia accusations and votes to kill cast in the current phase
+	ClearVotes(ctx context.Context, hostAddress string) error
+	// InjectPhaseExecution sends the given phase execution to everyone waiting for the current phase to be executed without changing the state of the game
+	InjectPhaseExecution(ctx context.Context, hostAddress string, phaseExecution *PhaseExecution) error
+	// SetCurrentPhase changes the time of day of the game; votes already cast are kept
+	SetCurrentPhase(ctx context.Context, hostAddress string, timeOfDay TimeOfDay) error
+	SetPlayerRole(ctx context.Context, hostAddress string, playerAddress string, playerRole PlayerRole) error
+	// SetPlayerStatus marks a player as dead, convicted, both, or neither; votes cast by or against a player who can no longer act are discarded
+	SetPlayerStatus(ctx context.Context, hostAddress string, playerAddress string, dead bool, convicted bool) error
+}
+
+func (i *InMemoryEngine) ClearVotes(ctx context.Context, hostAddress string) error {
+	return i.runGameAction(ctx, hostAddress, func(g *gameState) error {
+		g.clearVotes()
+
+		return nil
+	})
+}
+
+func (i *InMemoryEngine) InjectPhaseExecution(ctx context.Context, hostAddress string, phaseExecution *PhaseExecution) error {
+	injectedExecution := *phaseExecution
+	injectedExecution.HostAddress = hostAddress
+
+	return i.runGameAction(ctx, hostAddress, func(g *gameState) error {
+		g.logger.InfoContext(ctx, "injected phase execution", "timeOfDay", injectedExecution.CurrentPhase, "phaseOutcome", injectedExecution.PhaseOutcome)
+
+		g.publishPhaseExecution(ctx, &injectedExecution)
+
+		return nil
+	})
+}
+
+func (i *InMemoryEngine) SetCurrentPhase(ctx context.Context, hostAddress string, timeOfDay TimeOfDay) error {
+	return i.runGameAction(ctx, hostAddress, func(g *gameState) error {
+		switch timeOfDay {
+		case TimeOfDayDay, TimeOfDayNight:
+		default:
+			return newError(ErrInvalidRequest, "unknown time of day: %d", timeOfDay)
+		}
+
+		g.currentPhase = timeOfDay
+
+		return nil
+	})
+}
+
+func (i *InMemoryEngine) SetPlayerRole(ctx context.Context, hostAddress string, playerAddress string, playerRole PlayerRole) error {
+	return i.runGameAction(ctx, hostAddress, func(g *gameState) error {
+		switch playerRole {
+		case PlayerRoleCivilian, PlayerRoleMafia:
+		default:
+			return newError(ErrInvalidRequest, "unknown player role: %d", playerRole)
+		}
+
+		player := g.getPlayer(playerAddress)
+		if player == nil {
+			return newError(ErrNotFound, "player '%s' is not a member of the game", playerAddress)
+		}
+
+		player.PlayerRole = playerRole
+
+		return nil
+	})
+}
+
+func (i *InMemoryEngine) SetPlayerStatus(ctx context.Context, hostAddress string, playerAddress string, dead bool, convicted bool) error {
+	return i.runGameAction(ctx, hostAddress, func(g *gameState) error {
+		player := g.getPlayer(playerAddress)
+		if player == nil {
+			return newError(ErrNotFound, "player '%s' is not a member of the game", playerAddress)
+		}
+
+		player.Dead = dead
+		player.Convicted = convicted
+
+		if !player.CanAct() {
+			g.removeVotesInvolving(playerAddress)
+		}
+
+		return nil
+	})
+}
+
+func (g *gameState) clearVotes() {
+	g.mafiaAccusations = make(map[string]string)
+	g.killVotes = make(map[string]string)
+}
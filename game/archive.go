@@ -0,0 +1,63 @@
+package game
+
+import (
+	"strconv"
+	"time"
+)
+
+// ArchivedGame is the final record of a game that has been finished or cancelled
+type ArchivedGame struct {
+	ID              string
+	HostAddress     string
+	Cancelled       bool
+	PhaseOutcome    PhaseOutcome
+	Players         []*Player
+	PhaseExecutions []*PhaseExecution
+	CreatedAt       time.Time
+	StartedAt       time.Time
+	EndedAt         time.Time
+}
+
+// archiveGame adds the given game, which has ended, to the archive; this must be called by the game's actor
+func (i *InMemoryEngine) archiveGame(hostAddress string, gameState *gameState, cancelled bool) {
+	playersCopy := copyPlayers(gameState.getPlayers())
+	phaseExecutions := gameState.getPhaseExecutions()
+
+	i.archiveMutex.Lock()
+	defer i.archiveMutex.Unlock()
+
+	i.archiveSequence++
+	i.archivedGames = append(i.archivedGames, &ArchivedGame{
+		ID:              strconv.Itoa(i.archiveSequence),
+		HostAddress:     hostAddress,
+		Cancelled:       cancelled,
+		PhaseOutcome:    gameState.getLastPhaseOutcome(),
+		Players:         playersCopy,
+		PhaseExecutions: phaseExecutions,
+		CreatedAt:       gameState.createdAt,
+		StartedAt:       gameState.startedAt,
+		EndedAt:         time.Now(),
+	})
+}
+
+func (i *InMemoryEngine) getArchivedGame(gameID string) *ArchivedGame {
+	i.archiveMutex.RLock()
+	defer i.archiveMutex.RUnlock()
+
+	for _, archivedGame := range i.archivedGames {
+		if archivedGame.ID == gameID {
+			return archivedGame
+		}
+	}
+
+	return nil
+}
+
+func (i *InMemoryEngine) getArchivedGames() []*ArchivedGame {
+	i.archiveMutex.RLock()
+	defer i.archiveMutex.RUnlock()
+
+	archivedGames := make([]*ArchivedGame, len(i.archivedGames))
+	copy(archivedGames, i.archivedGames)
+	return archivedGames
+}
@@ -0,0 +1,93 @@
+package game
+
+import (
+	"context"
+	"sync"
+)
+
+// broadcaster delivers a single value to everyone waiting on it, such as the execution of a phase to everyone waiting for it.
+// Each subscription has room for the one value that it receives, so publishing never blocks on a subscriber that is slow or has stopped waiting.
+// Subscribers that stop waiting unsubscribe, so that a game with many abandoned waiters does not hold on to them.
+type broadcaster[T any] struct {
+	mutex         sync.Mutex
+	subscriptions map[*subscription[T]]struct{}
+}
+
+// subscription receives the next value published by a broadcaster
+type subscription[T any] struct {
+	broadcaster *broadcaster[T]
+	values      chan T
+}
+
+func newBroadcaster[T any]() *broadcaster[T] {
+	return &broadcaster[T]{
+		subscriptions: make(map[*subscription[T]]struct{}),
+	}
+}
+
+// publish sends the given value to every current subscriber, who are then unsubscribed; this returns the number of subscribers sent the value
+func (b *broadcaster[T]) publish(value T) int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for sub := range b.subscriptions {
+		// never blocks, since each subscription is buffered for the one value that it is ever sent
+		sub.values <- value
+	}
+
+	subscriberCount := len(b.subscriptions)
+	b.subscriptions = make(map[*subscription[T]]struct{})
+	return subscriberCount
+}
+
+// subscribe subscribes to the next value to be published.
+// The subscription is ended once the subscriber stops waiting on it, whether or not it received a value.
+func (b *broadcaster[T]) subscribe() *subscription[T] {
+	sub := &subscription[T]{
+		broadcaster: b,
+		values:      make(chan T, 1),
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.subscriptions[sub] = struct{}{}
+
+	return sub
+}
+
+// subscriberCount gets the number of subscribers waiting for the next value
+func (b *broadcaster[T]) subscriberCount() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return len(b.subscriptions)
+}
+
+// unsubscribe ends the given subscription; ending a subscription that has already been sent a value does nothing
+func (b *broadcaster[T]) unsubscribe(sub *subscription[T]) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.subscriptions, sub)
+}
+
+// wait waits for the value published to the subscription, ending the subscription if the given context ends or either of the given channels is closed first.
+// If shuttingDown is closed first, this returns an error matching ErrShuttingDown; if gameEnded is closed first, this returns an error matching ErrNotFound.
+func (s *subscription[T]) wait(ctx context.Context, shuttingDown <-chan struct{}, gameEnded <-chan struct{}) (T, error) {
+	defer s.broadcaster.unsubscribe(s)
+
+	select {
+	case value := <-s.values:
+		return value, nil
+	case <-shuttingDown:
+		var zero T
+		return zero, newShuttingDownError()
+	case <-gameEnded:
+		var zero T
+		return zero, newGameEndedError()
+	case <-ctx.Done():
+		var zero T
+		return zero, context.Cause(ctx)
+	}
+}
@@ -0,0 +1,50 @@
+package game
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("broadcaster", func() {
+	It("forgets subscribers that stop waiting", func() {
+		valueBroadcaster := newBroadcaster[int]()
+
+		for waiterIndex := 0; waiterIndex < 1000; waiterIndex++ {
+			waitCtx, cancelWait := context.WithTimeout(context.Background(), time.Millisecond)
+			_, err := valueBroadcaster.subscribe().wait(waitCtx, nil, nil)
+			cancelWait()
+			Expect(err).To(MatchError(context.DeadlineExceeded), "the waiter should stop waiting when its context ends")
+		}
+
+		Expect(valueBroadcaster.subscriberCount()).To(BeZero(), "subscribers that stopped waiting should be forgotten")
+	})
+
+	It("publishes without waiting for subscribers to receive", func() {
+		valueBroadcaster := newBroadcaster[int]()
+		unreadSub := valueBroadcaster.subscribe()
+
+		published := make(chan int, 1)
+		go func() {
+			published <- valueBroadcaster.publish(1)
+		}()
+		Eventually(published).Should(Receive(Equal(1)), "publishing should not wait for the subscriber to read the value")
+		Expect(valueBroadcaster.subscriberCount()).To(BeZero(), "subscribers should be forgotten once they are sent a value")
+
+		value, err := unreadSub.wait(context.Background(), nil, nil)
+		Expect(err).ToNot(HaveOccurred(), "waiting for a value already published should not fail")
+		Expect(value).To(Equal(1), "the subscriber should receive the published value")
+	})
+
+	It("releases subscribers when the game ends", func() {
+		valueBroadcaster := newBroadcaster[int]()
+		gameEnded := make(chan struct{})
+		close(gameEnded)
+
+		_, err := valueBroadcaster.subscribe().wait(context.Background(), nil, gameEnded)
+		Expect(err).To(MatchError(ErrNotFound), "the subscriber should be told that the game is gone")
+		Expect(valueBroadcaster.subscriberCount()).To(BeZero(), "subscribers released by the end of the game should be forgotten")
+	})
+})
@@ -0,0 +1,83 @@
+package game
+
+import (
+	"context"
+	"errors"
+)
+
+// CensusEngine reports on all of the games hosted by an engine, such as to export them as metrics
+type CensusEngine interface {
+	GetCensus(ctx context.Context) (*Census, error)
+}
+
+// Census is a snapshot of the games being played and running totals of what has happened in all games since the engine was built
+type Census struct {
+	// ActiveGames maps the host address of every game that has not been finished or cancelled to its number of players
+	ActiveGames  map[string]int
+	GamesStarted int
+	// GamesFinished counts finished games by the outcome of their last phase; a game finished before anyone won is counted as a continuation
+	GamesFinished   map[PhaseOutcome]int
+	GamesCancelled  int
+	PhaseExecutions map[TimeOfDay]int
+}
+
+func (i *InMemoryEngine) GetCensus(ctx context.Context) (*Census, error) {
+	census := &Census{
+		ActiveGames:     make(map[string]int),
+		GamesFinished:   make(map[PhaseOutcome]int),
+		PhaseExecutions: make(map[TimeOfDay]int),
+	}
+
+	for _, actor := range i.getGameActors() {
+		playerCount, err := runCommand(ctx, actor, func(g *gameState) (int, error) {
+			return len(g.players), nil
+		})
+		if errors.Is(err, ErrNotFound) {
+			// the game ended after the actors were listed
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		census.ActiveGames[actor.hostAddress] = playerCount
+	}
+
+	i.censusMutex.RLock()
+	defer i.censusMutex.RUnlock()
+
+	census.GamesStarted = i.gamesStarted
+	census.GamesCancelled = i.gamesCancelled
+	for phaseOutcome, count := range i.gamesFinished {
+		census.GamesFinished[phaseOutcome] = count
+	}
+	for timeOfDay, count := range i.phaseExecutions {
+		census.PhaseExecutions[timeOfDay] = count
+	}
+
+	return census, nil
+}
+
+func (i *InMemoryEngine) recordGameEnded(gameState *gameState, cancelled bool) {
+	i.censusMutex.Lock()
+	defer i.censusMutex.Unlock()
+
+	if cancelled {
+		i.gamesCancelled++
+	} else {
+		i.gamesFinished[gameState.getLastPhaseOutcome()]++
+	}
+}
+
+func (i *InMemoryEngine) recordGameStarted() {
+	i.censusMutex.Lock()
+	defer i.censusMutex.Unlock()
+
+	i.gamesStarted++
+}
+
+func (i *InMemoryEngine) recordPhaseExecution(timeOfDay TimeOfDay) {
+	i.censusMutex.Lock()
+	defer i.censusMutex.Unlock()
+
+	i.phaseExecutions[timeOfDay]++
+}
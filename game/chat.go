@@ -0,0 +1,117 @@
+package game
+
+import (
+	"fmt"
+	"time"
+)
+
+type ChatChannel string
+
+// ChatChannelPublic is the channel that all players can read and that living players can post to during the day
+const ChatChannelPublic ChatChannel = "public"
+
+// ChatChannelMafia is the channel that only the Mafia can read and that living Mafia members can post to during the night
+const ChatChannelMafia ChatChannel = "mafia"
+
+type ChatMessage struct {
+	Sequence       int
+	Channel        ChatChannel
+	SenderAddress  string
+	SenderNickname string
+	Message        string
+	TimeOfDay      TimeOfDay
+	SentAt         time.Time
+}
+
+// getChatMessages gets all messages in the given channel with a sequence number greater than the given sequence number.
+// This also returns a channel that will be closed the next time a message is posted to any chat channel in the game.
+func (g *gameState) getChatMessages(readerAddress string, channel ChatChannel, afterSequence int) ([]*ChatMessage, <-chan struct{}, error) {
+	if err := g.verifyCanReadChat(readerAddress, channel); err != nil {
+		return nil, nil, err
+	}
+
+	var messages []*ChatMessage
+	for _, message := range g.chatMessages[channel] {
+		if message.Sequence > afterSequence {
+			messages = append(messages, message)
+		}
+	}
+
+	return messages, g.chatUpdated, nil
+}
+
+func (g *gameState) postChatMessage(senderAddress string, channel ChatChannel, message string) (*ChatMessage, error) {
+	if message == "" {
+		return nil, newError(ErrInvalidRequest, "a chat message cannot be empty")
+	}
+
+	if !g.started {
+		return nil, newError(ErrInvalidPhase, "chat messages can only be posted once the game has started")
+	}
+
+	sender := g.getPlayer(senderAddress)
+	if sender == nil {
+		return nil, newError(ErrForbidden, "sender '%s' must be a member of the game", senderAddress)
+	} else if !sender.CanAct() {
+		return nil, newError(ErrForbidden, "sender '%s' must be able to take actions in the game", senderAddress)
+	}
+
+	currentPhase := g.currentPhase
+	switch channel {
+	case ChatChannelPublic:
+		if currentPhase != TimeOfDayDay {
+			return nil, newError(ErrInvalidPhase, "public chat messages can only be posted during the day")
+		}
+	case ChatChannelMafia:
+		if sender.PlayerRole != PlayerRoleMafia {
+			return nil, newError(ErrForbidden, "only members of the Mafia can post to the Mafia chat")
+		} else if currentPhase != TimeOfDayNight {
+			return nil, newError(ErrInvalidPhase, "Mafia chat messages can only be posted during the night")
+		}
+	default:
+		return nil, fmt.Errorf("unhandled chat channel: %s", channel)
+	}
+
+	chatMessage := &ChatMessage{
+		Sequence:       len(g.chatMessages[channel]) + 1,
+		Channel:        channel,
+		SenderAddress:  sender.PlayerAddress,
+		SenderNickname: sender.PlayerNickname,
+		Message:        message,
+		TimeOfDay:      currentPhase,
+		SentAt:         time.Now(),
+	}
+	g.chatMessages[channel] = append(g.chatMessages[channel], chatMessage)
+
+	// wake up everyone waiting on new messages
+	close(g.chatUpdated)
+	g.chatUpdated = make(chan struct{})
+
+	return chatMessage, nil
+}
+
+// verifyCanReadChat determines if the given player is allowed to read the given chat channel.
+// Dead and convicted players can still read the channels that they could read while they were able to act.
+// Spectators can read the public channel.
+func (g *gameState) verifyCanReadChat(readerAddress string, channel ChatChannel) error {
+	reader := g.getPlayer(readerAddress)
+	if reader == nil {
+		if spectator := g.getSpectator(readerAddress); spectator != nil && channel == ChatChannelPublic {
+			return nil
+		}
+
+		return newError(ErrForbidden, "reader '%s' must be a member of the game", readerAddress)
+	}
+
+	switch channel {
+	case ChatChannelPublic:
+		return nil
+	case ChatChannelMafia:
+		if reader.PlayerRole != PlayerRoleMafia {
+			return newError(ErrForbidden, "only members of the Mafia can read the Mafia chat")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unhandled chat channel: %s", channel)
+	}
+}
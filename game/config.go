@@ -0,0 +1,204 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// MinimumPlayerCount is the fewest players with which a game can be played; any fewer and the Mafia would win immediately
+const MinimumPlayerCount = 3
+
+// DefaultMafiaRatio is the default number of players for which one member of the Mafia is assigned
+const DefaultMafiaRatio = 5
+
+// MinimumMafiaRatio is the smallest allowed number of players for which one member of the Mafia is assigned
+const MinimumMafiaRatio = 3
+
+// TiePolicy describes how a vote is resolved when more than one player shares the highest number of votes
+type TiePolicy string
+
+// TiePolicyNoElimination resolves ties by eliminating no one
+const TiePolicyNoElimination TiePolicy = "none"
+
+// TiePolicyRandom resolves ties by eliminating one of the tied players at random
+const TiePolicyRandom TiePolicy = "random"
+
+// TiePolicyEliminateAll resolves ties by eliminating all of the tied players
+const TiePolicyEliminateAll TiePolicy = "all"
+
+// GameConfig describes the rules under which a game is played
+type GameConfig struct {
+	// Private games are not listed in the lobby
+	Private bool
+	// MinPlayers is the minimum number of players that must join the game before it can be started
+	MinPlayers int
+	// MaxPlayers is the maximum number of players that can join the game; zero means there is no limit
+	MaxPlayers int
+	// MafiaCount is the exact number of players assigned to the Mafia; zero means that MafiaRatio is used instead
+	MafiaCount int
+	// MafiaRatio assigns one member of the Mafia for every MafiaRatio players, rounded up
+	MafiaRatio int
+	TiePolicy  TiePolicy
+	// DayDuration is how long the day lasts before the phase is executed automatically; zero means the host must execute it
+	DayDuration time.Duration
+	// NightDuration is how long the night lasts before the phase is executed automatically; zero means the host must execute it
+	NightDuration time.Duration
+	StartingPhase TimeOfDay
+	// RevealRoleOnDeath reveals the roles of dead and convicted players to all players
+	RevealRoleOnDeath bool
+	// AllowGodView allows spectators to see the roles of all players and the votes cast so far in the current phase, both the Mafia accusations of the day and the votes to kill of the night
+	AllowGodView bool
+	// ForcedRoles assigns roles to players by address instead of at random; the remaining Mafia members are chosen at random from the other players
+	ForcedRoles map[string]PlayerRole
+}
+
+// DefaultGameConfig builds the configuration used for games initialized without a configuration
+func DefaultGameConfig() *GameConfig {
+	return &GameConfig{
+		MinPlayers: MinimumPlayerCount,
+		MafiaRatio: DefaultMafiaRatio,
+		TiePolicy:  TiePolicyNoElimination,
+	}
+}
+
+// Validate determines whether the configuration describes a playable game
+func (c *GameConfig) Validate() error {
+	if c.MinPlayers < MinimumPlayerCount {
+		return fmt.Errorf("the minimum number of players must be at least %d", MinimumPlayerCount)
+	}
+
+	if c.MaxPlayers < 0 {
+		return errors.New("the maximum number of players cannot be negative")
+	} else if c.MaxPlayers > 0 && c.MaxPlayers < c.MinPlayers {
+		return fmt.Errorf("the maximum number of players (%d) cannot be less than the minimum number of players (%d)", c.MaxPlayers, c.MinPlayers)
+	}
+
+	if c.MafiaCount < 0 {
+		return errors.New("the number of Mafia members cannot be negative")
+	} else if c.MafiaCount == 0 && c.MafiaRatio < MinimumMafiaRatio {
+		return fmt.Errorf("there must be at least %d players for every Mafia member", MinimumMafiaRatio)
+	}
+
+	// the Mafia would win immediately if they were not outnumbered by civilians.
+	// An exact Mafia count is only more outnumbered as players join, as is a ratio of at least MinimumMafiaRatio once a game has more than MafiaRatio players beyond the minimum,
+	// so only the smallest games that can be started need to be checked.
+	largestCheckedPlayerCount := c.MinPlayers + c.MafiaRatio
+	if c.MaxPlayers > 0 && c.MaxPlayers < largestCheckedPlayerCount {
+		largestCheckedPlayerCount = c.MaxPlayers
+	}
+	for playerCount := c.MinPlayers; playerCount <= largestCheckedPlayerCount; playerCount++ {
+		if mafiaCount := c.getMafiaCount(playerCount); mafiaCount*2 >= playerCount {
+			return fmt.Errorf("a game of %d players would have %d Mafia members, who must be outnumbered by the civilians", playerCount, mafiaCount)
+		}
+	}
+
+	switch c.TiePolicy {
+	case TiePolicyNoElimination, TiePolicyRandom, TiePolicyEliminateAll:
+	default:
+		return fmt.Errorf("unknown tie policy: '%s'", c.TiePolicy)
+	}
+
+	if c.DayDuration < 0 {
+		return errors.New("the day duration cannot be negative")
+	} else if c.NightDuration < 0 {
+		return errors.New("the night duration cannot be negative")
+	}
+
+	switch c.StartingPhase {
+	case TimeOfDayDay, TimeOfDayNight:
+	default:
+		return fmt.Errorf("unknown starting phase: %d", c.StartingPhase)
+	}
+
+	for playerAddress, playerRole := range c.ForcedRoles {
+		switch playerRole {
+		case PlayerRoleCivilian, PlayerRoleMafia:
+		default:
+			return fmt.Errorf("unknown role forced upon player '%s': %d", playerAddress, playerRole)
+		}
+	}
+
+	return nil
+}
+
+// assignRoles assigns a role to each of the given players, honoring any forced roles.
+// Forced roles that cannot be honored given the players who joined fail with an error matching ErrConflict.
+func (c *GameConfig) assignRoles(players []*Player) error {
+	mafiaCount := c.getMafiaCount(len(players))
+
+	playersByAddress := make(map[string]*Player, len(players))
+	for _, player := range players {
+		playersByAddress[player.PlayerAddress] = player
+	}
+
+	var forcedMafiaCount int
+	for playerAddress, playerRole := range c.ForcedRoles {
+		if _, isPlayer := playersByAddress[playerAddress]; !isPlayer {
+			return newError(ErrConflict, "a role cannot be forced upon '%s', who is not a member of the game", playerAddress)
+		}
+
+		if playerRole == PlayerRoleMafia {
+			forcedMafiaCount++
+		}
+	}
+
+	if forcedMafiaCount > mafiaCount {
+		return newError(ErrConflict, "%d players were forced into the Mafia, but only %d Mafia members are allowed", forcedMafiaCount, mafiaCount)
+	}
+
+	var unforcedPlayers []*Player
+	for _, player := range players {
+		if forcedRole, isForced := c.ForcedRoles[player.PlayerAddress]; isForced {
+			player.PlayerRole = forcedRole
+		} else {
+			unforcedPlayers = append(unforcedPlayers, player)
+		}
+	}
+
+	remainingMafiaCount := mafiaCount - forcedMafiaCount
+	if remainingMafiaCount > len(unforcedPlayers) {
+		return newError(ErrConflict, "too many players were forced to be civilians to assign %d Mafia members", mafiaCount)
+	}
+
+	// the players come from a map, so put them in a fixed order first so that a seeded random source always assigns the same roles
+	sort.Slice(unforcedPlayers, func(i, j int) bool {
+		return unforcedPlayers[i].PlayerAddress < unforcedPlayers[j].PlayerAddress
+	})
+	rand.Shuffle(len(unforcedPlayers), func(i, j int) {
+		unforcedPlayers[i], unforcedPlayers[j] = unforcedPlayers[j], unforcedPlayers[i]
+	})
+	for playerIndex, player := range unforcedPlayers {
+		if playerIndex < remainingMafiaCount {
+			player.PlayerRole = PlayerRoleMafia
+		} else {
+			player.PlayerRole = PlayerRoleCivilian
+		}
+	}
+
+	return nil
+}
+
+// getMafiaCount determines how many of the given number of players are to be assigned to the Mafia
+func (c *GameConfig) getMafiaCount(playerCount int) int {
+	if c.MafiaCount > 0 {
+		return c.MafiaCount
+	}
+
+	return int(math.Ceil(float64(playerCount) / float64(c.MafiaRatio)))
+}
+
+// getPhaseDuration gets how long the given phase lasts before being executed automatically; zero means that it is not executed automatically
+func (c *GameConfig) getPhaseDuration(timeOfDay TimeOfDay) time.Duration {
+	switch timeOfDay {
+	case TimeOfDayDay:
+		return c.DayDuration
+	case TimeOfDayNight:
+		return c.NightDuration
+	default:
+		return 0
+	}
+}
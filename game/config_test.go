@@ -0,0 +1,48 @@
+package game_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+var _ = Describe("GameConfig", func() {
+	DescribeTable("validates the number of Mafia members against the number of players",
+		func(configure func(gameConfig *game.GameConfig), expectValid bool) {
+			gameConfig := game.DefaultGameConfig()
+			configure(gameConfig)
+
+			if expectValid {
+				Expect(gameConfig.Validate()).To(Succeed(), "the configuration should be valid")
+			} else {
+				Expect(gameConfig.Validate()).To(MatchError(ContainSubstring("must be outnumbered by the civilians")), "the configuration should be rejected")
+			}
+		},
+		Entry("default configuration", func(gameConfig *game.GameConfig) {}, true),
+		Entry("ratio that ties the Mafia with the civilians once a fourth player joins", func(gameConfig *game.GameConfig) {
+			gameConfig.MafiaRatio = 3
+		}, false),
+		Entry("ratio whose smallest game is large enough", func(gameConfig *game.GameConfig) {
+			gameConfig.MafiaRatio = 3
+			gameConfig.MinPlayers = 5
+		}, true),
+		Entry("ratio whose games are too small to tie", func(gameConfig *game.GameConfig) {
+			gameConfig.MafiaRatio = 3
+			gameConfig.MaxPlayers = 3
+		}, true),
+		Entry("exact count that ties the Mafia with the civilians", func(gameConfig *game.GameConfig) {
+			gameConfig.MafiaCount = 2
+			gameConfig.MinPlayers = 4
+		}, false),
+		Entry("exact count outnumbered by the civilians", func(gameConfig *game.GameConfig) {
+			gameConfig.MafiaCount = 2
+			gameConfig.MinPlayers = 5
+		}, true),
+		Entry("exact count that outnumbers every game allowed", func(gameConfig *game.GameConfig) {
+			gameConfig.MafiaCount = 3
+			gameConfig.MinPlayers = 5
+			gameConfig.MaxPlayers = 5
+		}, false),
+	)
+})
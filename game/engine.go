@@ -1,19 +1,35 @@
 package game
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 type Engine interface {
 	AccuseAsMafia(ctx context.Context, hostAddress string, accuserAddress string, accuseeAddress string) error
+	AddSpectator(ctx context.Context, hostAddress string, spectatorAddress string, godView bool) error
 	CancelGame(ctx context.Context, hostAddress string) error
-	ExecutePhase(ctx context.Context, hostAddress string) error
+	ExecutePhase(ctx context.Context, hostAddress string) (*PhaseExecution, error)
 	FinishGame(ctx context.Context, hostAddress string) error
+	GetArchivedGame(ctx context.Context, gameID string) (*ArchivedGame, error)
+	GetArchivedGames(ctx context.Context) ([]*ArchivedGame, error)
+	GetChatMessages(ctx context.Context, hostAddress string, readerAddress string, channel ChatChannel, afterSequence int) ([]*ChatMessage, error)
+	GetGameConfig(ctx context.Context, hostAddress string) (*GameConfig, error)
+	GetLobby(ctx context.Context) (*Lobby, error)
 	GetPlayer(ctx context.Context, hostAddress string, playerAddress string) (*Player, error)
+	GetPlayerStats(ctx context.Context, playerAddress string) (*PlayerStats, error)
 	GetPlayers(ctx context.Context, hostAddress string) ([]*Player, error)
-	InitializeGame(ctx context.Context, hostAddress string) error
+	GetSpectator(ctx context.Context, hostAddress string, spectatorAddress string) (*Spectator, error)
+	GetVotes(ctx context.Context, hostAddress string) (*Votes, error)
+	InitializeGame(ctx context.Context, hostAddress string, config *GameConfig) error
 	JoinGame(ctx context.Context, hostAddress string, playerAddress string, playerNickname string) error
+	LeaveGame(ctx context.Context, hostAddress string, requesterAddress string, playerAddress string) error
+	PostChatMessage(ctx context.Context, hostAddress string, senderAddress string, channel ChatChannel, message string) (*ChatMessage, error)
 	StartGame(ctx context.Context, hostAddress string) error
 	VoteToKill(ctx context.Context, hostAddress string, killerAddress string, killeeAddress string) error
+	WaitForChatMessages(ctx context.Context, hostAddress string, readerAddress string, channel ChatChannel, afterSequence int) ([]*ChatMessage, error)
 	WaitForGameStart(ctx context.Context, hostAddress string) error
+	WaitForLobbyUpdate(ctx context.Context, sinceVersion int) (*Lobby, error)
 	WaitForPhaseExecution(ctx context.Context, hostAddress string) (*PhaseExecution, error)
 }
 
@@ -23,11 +39,35 @@ const PhaseOutcomeContinuation PhaseOutcome = 0
 const PhaseOutcomeCivilianVictory PhaseOutcome = 1
 const PhaseOutcomeMafiaVictory PhaseOutcome = 2
 
+func (p PhaseOutcome) String() string {
+	switch p {
+	case PhaseOutcomeContinuation:
+		return "continuation"
+	case PhaseOutcomeCivilianVictory:
+		return "civilian victory"
+	case PhaseOutcomeMafiaVictory:
+		return "Mafia victory"
+	default:
+		return fmt.Sprintf("PhaseOutcome(%d)", int(p))
+	}
+}
+
 type TimeOfDay int
 
 const TimeOfDayDay TimeOfDay = 0
 const TimeOfDayNight TimeOfDay = 1
 
+func (t TimeOfDay) String() string {
+	switch t {
+	case TimeOfDayDay:
+		return "day"
+	case TimeOfDayNight:
+		return "night"
+	default:
+		return fmt.Sprintf("TimeOfDay(%d)", int(t))
+	}
+}
+
 type PlayerRole int
 
 const PlayerRoleCivilian PlayerRole = 0
@@ -39,6 +79,7 @@ type PhaseExecution struct {
 	CurrentPhase     TimeOfDay
 	KilledPlayers    []string
 	ConvictedPlayers []string
+	ForfeitedPlayers []string
 }
 
 type Player struct {
@@ -47,6 +88,8 @@ type Player struct {
 	PlayerRole     PlayerRole
 	Dead           bool
 	Convicted      bool
+	// Forfeited indicates that the player left the game after it started; forfeited players are also dead
+	Forfeited bool
 }
 
 // CanAct determines if the user is able to make actions within the game
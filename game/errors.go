@@ -0,0 +1,94 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is matched by errors returned when a game or player does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is matched by errors returned when an action has already been taken or cannot be taken given the state of the game
+var ErrConflict = errors.New("conflict")
+
+// ErrForbidden is matched by errors returned when the requester is not allowed to take an action
+var ErrForbidden = errors.New("forbidden")
+
+// ErrInvalidPhase is matched by errors returned when an action is taken at the wrong point in the game, such as voting to kill during the day
+var ErrInvalidPhase = errors.New("invalid phase")
+
+// ErrInvalidTarget is matched by errors returned when an action targets a player who cannot be targeted
+var ErrInvalidTarget = errors.New("invalid target")
+
+// ErrInvalidRequest is matched by errors returned when a request is malformed regardless of the state of the game, such as an empty chat message or an invalid game configuration
+var ErrInvalidRequest = errors.New("invalid request")
+
+// ErrShuttingDown is matched by errors returned when the engine is shutting down, such as to every waiter released by the shutdown; the request can be retried once the server is back
+var ErrShuttingDown = errors.New("shutting down")
+
+// GameFullError is returned when a player attempts to join a game that already has as many players as it allows
+type GameFullError struct {
+	MaxPlayers int
+}
+
+func (e *GameFullError) Error() string {
+	return fmt.Sprintf("the game cannot have more than %d players", e.MaxPlayers)
+}
+
+func (e *GameFullError) Unwrap() error {
+	return ErrConflict
+}
+
+// NotEnoughPlayersError is returned when a game is started before enough players have joined it
+type NotEnoughPlayersError struct {
+	PlayerCount int
+	MinPlayers  int
+}
+
+func (e *NotEnoughPlayersError) Error() string {
+	return fmt.Sprintf("the game needs at least %d players to start, but only %d have joined", e.MinPlayers, e.PlayerCount)
+}
+
+func (e *NotEnoughPlayersError) Unwrap() error {
+	return ErrConflict
+}
+
+// PlayersNeeded is the number of additional players that must join before the game can be started
+func (e *NotEnoughPlayersError) PlayersNeeded() int {
+	return e.MinPlayers - e.PlayerCount
+}
+
+// engineError keeps the message describing a failure free of the name of the sentinel error that it matches
+type engineError struct {
+	kind    error
+	message string
+}
+
+func (e *engineError) Error() string {
+	return e.message
+}
+
+func (e *engineError) Unwrap() error {
+	return e.kind
+}
+
+// newError builds an error with the formatted message that matches the given sentinel error
+func newError(kind error, format string, args ...any) error {
+	return &engineError{
+		kind:    kind,
+		message: fmt.Sprintf(format, args...),
+	}
+}
+
+func newGameNotFoundError(hostAddress string) error {
+	return newError(ErrNotFound, "no game found for host address '%s'", hostAddress)
+}
+
+// newGameEndedError describes a game that ended while it was being waited on
+func newGameEndedError() error {
+	return newError(ErrNotFound, "the game has ended")
+}
+
+func newShuttingDownError() error {
+	return newError(ErrShuttingDown, "the server is shutting down")
+}
@@ -0,0 +1,13 @@
+package game_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGame(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Game Suite")
+}
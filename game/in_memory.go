@@ -4,285 +4,617 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math"
+	"log/slog"
 	"math/rand"
+	"sort"
 	"sync"
+	"time"
 )
 
 type InMemoryEngine struct {
-	gameStatesMutex sync.RWMutex
-	gameStates      map[string]*gameState
+	logger *slog.Logger
+
+	// gameActors are the actors running every game that has not ended, keyed by the address of the host
+	gameActorsMutex sync.RWMutex
+	gameActors      map[string]*gameActor
+
+	archiveMutex    sync.RWMutex
+	archivedGames   []*ArchivedGame
+	archiveSequence int
+
+	statsMutex  sync.RWMutex
+	playerStats map[string]*PlayerStats
+
+	lobbyMutex   sync.RWMutex
+	lobbyVersion int
+	lobbyUpdated chan struct{}
+
+	censusMutex     sync.RWMutex
+	gamesStarted    int
+	gamesFinished   map[PhaseOutcome]int
+	gamesCancelled  int
+	phaseExecutions map[TimeOfDay]int
+
+	// shuttingDown is closed once the engine has been shut down, releasing every waiter
+	shuttingDown chan struct{}
+	shutdownOnce sync.Once
 }
 
-func NewInMemoryGameEngine() *InMemoryEngine {
+// NewInMemoryGameEngine builds an engine that keeps all games in memory; if no logger is given, then the default logger is used.
+// Each game is run by a goroutine of its own, which runs every command against the game in the order in which they arrive.
+func NewInMemoryGameEngine(logger *slog.Logger) *InMemoryEngine {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
 	return &InMemoryEngine{
-		gameStates: make(map[string]*gameState),
+		logger:          logger,
+		gameActors:      make(map[string]*gameActor),
+		playerStats:     make(map[string]*PlayerStats),
+		lobbyUpdated:    make(chan struct{}),
+		gamesFinished:   make(map[PhaseOutcome]int),
+		phaseExecutions: make(map[TimeOfDay]int),
+		shuttingDown:    make(chan struct{}),
 	}
 }
 
 func (i *InMemoryEngine) AccuseAsMafia(ctx context.Context, hostAddress string, accuserAddress string, accuseeAddress string) error {
-	gameState, hasGameState := i.getGameState(hostAddress)
-	if !hasGameState {
-		return fmt.Errorf("failed to find game state for host address '%s'", hostAddress)
-	}
+	return i.runGameAction(ctx, hostAddress, func(g *gameState) error {
+		if err := g.accuseAsMafia(accuserAddress, accuseeAddress); err != nil {
+			return err
+		}
 
-	return gameState.accuseAsMafia(accuserAddress, accuseeAddress)
+		g.logger.DebugContext(ctx, "accused player of being in the Mafia", "phase", g.getPhaseNumber()+1, "playerAddress", accuserAddress, "accuseeAddress", accuseeAddress)
+
+		return nil
+	})
 }
 
-func (i *InMemoryEngine) CancelGame(ctx context.Context, hostAddress string) error {
-	i.gameStatesMutex.Lock()
-	defer i.gameStatesMutex.Unlock()
+func (i *InMemoryEngine) AddSpectator(ctx context.Context, hostAddress string, spectatorAddress string, godView bool) error {
+	return i.runGameAction(ctx, hostAddress, func(g *gameState) error {
+		return g.addSpectator(&Spectator{
+			SpectatorAddress: spectatorAddress,
+			GodView:          godView,
+		})
+	})
+}
 
-	delete(i.gameStates, hostAddress)
+func (i *InMemoryEngine) CancelGame(ctx context.Context, hostAddress string) error {
+	i.endGame(ctx, hostAddress, true)
 
 	return nil
 }
 
-func (i *InMemoryEngine) ExecutePhase(ctx context.Context, hostAddress string) error {
-	gameState, hasGameState := i.getGameState(hostAddress)
-	if !hasGameState {
-		return fmt.Errorf("failed to find game state for host address '%s'", hostAddress)
+func (i *InMemoryEngine) ExecutePhase(ctx context.Context, hostAddress string) (*PhaseExecution, error) {
+	actor, hasActor := i.getGameActor(hostAddress)
+	if !hasActor {
+		return nil, newGameNotFoundError(hostAddress)
 	}
 
-	currentPhase := gameState.getCurrentPhase()
+	return i.executePhase(ctx, actor, anyPhaseNumber)
+}
 
-	phaseExecution := &PhaseExecution{
-		HostAddress:  hostAddress,
-		CurrentPhase: currentPhase,
-	}
-	switch currentPhase {
-	case TimeOfDayDay:
-		accusedAddress, hasAccused := gameState.tallyMafiaVotes()
-		if hasAccused {
-			phaseExecution.ConvictedPlayers = append(phaseExecution.ConvictedPlayers, accusedAddress)
-		}
-	case TimeOfDayNight:
-		killedAddress, hasKilled := gameState.tallyKillVotes()
-		if hasKilled {
-			phaseExecution.KilledPlayers = append(phaseExecution.KilledPlayers, killedAddress)
-		}
-	default:
-		return fmt.Errorf("unhandled phase: %v", currentPhase)
-	}
+func (i *InMemoryEngine) FinishGame(ctx context.Context, hostAddress string) error {
+	i.endGame(ctx, hostAddress, false)
 
-	phaseExecution.PhaseOutcome = gameState.calculatePhaseOutcome()
+	return nil
+}
 
-	gameState.notifyOfPhaseExecution(phaseExecution)
+func (i *InMemoryEngine) GetArchivedGame(ctx context.Context, gameID string) (*ArchivedGame, error) {
+	return i.getArchivedGame(gameID), nil
+}
 
-	return nil
+func (i *InMemoryEngine) GetArchivedGames(ctx context.Context) ([]*ArchivedGame, error) {
+	return i.getArchivedGames(), nil
 }
 
-func (i *InMemoryEngine) FinishGame(ctx context.Context, hostAddress string) error {
-	i.gameStatesMutex.Lock()
-	defer i.gameStatesMutex.Unlock()
+func (i *InMemoryEngine) GetChatMessages(ctx context.Context, hostAddress string, readerAddress string, channel ChatChannel, afterSequence int) ([]*ChatMessage, error) {
+	return runGameCommand(ctx, i, hostAddress, func(g *gameState) ([]*ChatMessage, error) {
+		messages, _, err := g.getChatMessages(readerAddress, channel, afterSequence)
+		return messages, err
+	})
+}
 
-	delete(i.gameStates, hostAddress)
+func (i *InMemoryEngine) GetGameConfig(ctx context.Context, hostAddress string) (*GameConfig, error) {
+	return runGameCommand(ctx, i, hostAddress, func(g *gameState) (*GameConfig, error) {
+		configCopy := *g.config
+		return &configCopy, nil
+	})
+}
 
-	return nil
+func (i *InMemoryEngine) GetLobby(ctx context.Context) (*Lobby, error) {
+	lobby, _, err := i.getLobby(ctx)
+	return lobby, err
 }
 
 func (i *InMemoryEngine) GetPlayer(ctx context.Context, hostAddress string, playerAddress string) (*Player, error) {
-	gameState, hasGameState := i.getGameState(hostAddress)
-	if !hasGameState {
-		return nil, fmt.Errorf("no game found for host address '%s'", hostAddress)
-	}
+	return runGameCommand(ctx, i, hostAddress, func(g *gameState) (*Player, error) {
+		return copyPlayer(g.getPlayer(playerAddress)), nil
+	})
+}
 
-	return gameState.getPlayer(playerAddress), nil
+func (i *InMemoryEngine) GetPlayerStats(ctx context.Context, playerAddress string) (*PlayerStats, error) {
+	return i.getPlayerStats(playerAddress), nil
 }
 
 func (i *InMemoryEngine) GetPlayers(ctx context.Context, hostAddress string) ([]*Player, error) {
-	gameState, hasGameState := i.getGameState(hostAddress)
-	if !hasGameState {
-		return nil, fmt.Errorf("no game found for host address '%s'", hostAddress)
-	}
+	return runGameCommand(ctx, i, hostAddress, func(g *gameState) ([]*Player, error) {
+		return copyPlayers(g.getPlayers()), nil
+	})
+}
 
-	return gameState.getPlayers(), nil
+func (i *InMemoryEngine) GetSpectator(ctx context.Context, hostAddress string, spectatorAddress string) (*Spectator, error) {
+	return runGameCommand(ctx, i, hostAddress, func(g *gameState) (*Spectator, error) {
+		return g.getSpectator(spectatorAddress), nil
+	})
 }
 
-func (i *InMemoryEngine) InitializeGame(_ context.Context, hostAddress string) error {
-	i.gameStatesMutex.Lock()
-	defer i.gameStatesMutex.Unlock()
+func (i *InMemoryEngine) GetVotes(ctx context.Context, hostAddress string) (*Votes, error) {
+	return runGameCommand(ctx, i, hostAddress, func(g *gameState) (*Votes, error) {
+		return g.getVotes(), nil
+	})
+}
 
-	if _, hasGame := i.gameStates[hostAddress]; hasGame {
-		return errors.New("a game cannot be initialized twice")
+func (i *InMemoryEngine) InitializeGame(ctx context.Context, hostAddress string, config *GameConfig) error {
+	if config == nil {
+		config = DefaultGameConfig()
 	}
 
-	i.gameStates[hostAddress] = newGameState()
+	if err := config.Validate(); err != nil {
+		return newError(ErrInvalidRequest, "invalid game configuration: %v", err)
+	}
+
+	gameState := newGameState(config, i.logger.With("hostAddress", hostAddress))
+	if err := i.addGameActor(hostAddress, gameState); err != nil {
+		return err
+	}
+
+	gameState.logger.InfoContext(ctx, "initialized game", "minPlayers", config.MinPlayers, "maxPlayers", config.MaxPlayers, "private", config.Private)
+
+	i.notifyLobbyChanged()
 
 	return nil
 }
 
-func (i *InMemoryEngine) JoinGame(_ context.Context, hostAddress string, playerAddress string, playerNickname string) error {
-	game, hasGame := i.getGameState(hostAddress)
-	if !hasGame {
-		return errors.New("no game found")
-	} else if game.started {
-		return errors.New("cannot join a game already in progress")
-	}
+func (i *InMemoryEngine) JoinGame(ctx context.Context, hostAddress string, playerAddress string, playerNickname string) error {
+	err := i.runGameAction(ctx, hostAddress, func(g *gameState) error {
+		if g.started {
+			return newError(ErrInvalidPhase, "cannot join a game already in progress")
+		}
+
+		if player := g.getPlayer(playerAddress); player != nil {
+			return newError(ErrConflict, "cannot join a game multiple times")
+		}
+
+		if spectator := g.getSpectator(playerAddress); spectator != nil {
+			return newError(ErrConflict, "spectators cannot join a game as players")
+		}
+
+		if err := g.addPlayer(newPlayer(playerAddress, playerNickname)); err != nil {
+			return err
+		}
+
+		g.logger.InfoContext(ctx, "player joined game", "playerAddress", playerAddress)
 
-	if player := game.getPlayer(playerAddress); player != nil {
-		return errors.New("cannot join a game multiple times")
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	game.addPlayer(newPlayer(playerAddress, playerNickname))
+	i.notifyLobbyChanged()
 
 	return nil
 }
 
-func (i *InMemoryEngine) StartGame(_ context.Context, hostAddress string) error {
-	game, hasGame := i.getGameState(hostAddress)
-	if !hasGame {
-		return errors.New("a game cannot be started without initialization")
-	}
+func (i *InMemoryEngine) LeaveGame(ctx context.Context, hostAddress string, requesterAddress string, playerAddress string) error {
+	return i.runGameAction(ctx, hostAddress, func(g *gameState) error {
+		if !g.started {
+			if requesterAddress != playerAddress && requesterAddress != hostAddress {
+				return newError(ErrForbidden, "only the player or the host can remove a player from a game")
+			}
 
-	if game.started {
-		return errors.New("a game in progress cannot be started again")
-	}
+			if err := g.removePlayer(playerAddress); err != nil {
+				return err
+			}
 
-	// assign roles - one mafia for every five players, rounded up
-	players := game.getPlayers()
-	mafiaCount := int(math.Ceil(float64(len(players)) / 5))
-	playersCopy := make([]*Player, len(players))
-	copy(playersCopy, players)
-	rand.Shuffle(len(playersCopy), func(i, j int) {
-		old := playersCopy[i]
-		playersCopy[i] = playersCopy[j]
-		playersCopy[j] = old
-	})
-	for playerIndex, player := range playersCopy {
-		if playerIndex < mafiaCount {
-			player.PlayerRole = PlayerRoleMafia
-		} else {
-			player.PlayerRole = PlayerRoleCivilian
+			g.logger.InfoContext(ctx, "player left game", "playerAddress", playerAddress, "requesterAddress", requesterAddress)
+
+			i.notifyLobbyChanged()
+
+			return nil
+		}
+
+		// the host removing a player from a game in progress would be a kill that no one voted for
+		if requesterAddress != playerAddress {
+			return newError(ErrForbidden, "only the player can forfeit a game in progress")
+		}
+
+		if err := g.checkNotOver(); err != nil {
+			return err
+		}
+
+		// leaving a game in progress forfeits it
+		if err := g.forfeit(playerAddress); err != nil {
+			return err
+		}
+
+		g.logger.InfoContext(ctx, "player forfeited game", "playerAddress", playerAddress, "requesterAddress", requesterAddress)
+
+		if phaseOutcome := g.calculatePhaseOutcome(); phaseOutcome != PhaseOutcomeContinuation {
+			// the phase itself has not been executed, so it is left as it is
+			g.announceGameOver(ctx, &PhaseExecution{
+				HostAddress:      hostAddress,
+				PhaseOutcome:     phaseOutcome,
+				CurrentPhase:     g.currentPhase,
+				ForfeitedPlayers: []string{playerAddress},
+			})
+
+			i.recordStats(g, phaseOutcome)
 		}
+
+		return nil
+	})
+}
+
+func (i *InMemoryEngine) PostChatMessage(ctx context.Context, hostAddress string, senderAddress string, channel ChatChannel, message string) (*ChatMessage, error) {
+	return runGameCommand(ctx, i, hostAddress, func(g *gameState) (*ChatMessage, error) {
+		return g.postChatMessage(senderAddress, channel, message)
+	})
+}
+
+func (i *InMemoryEngine) StartGame(ctx context.Context, hostAddress string) error {
+	actor, hasActor := i.getGameActor(hostAddress)
+	if !hasActor {
+		return newError(ErrNotFound, "a game cannot be started without initialization")
 	}
 
-	if startErr := game.announceStart(); startErr != nil {
-		return fmt.Errorf("failed to start game: %w", startErr)
+	_, err := runCommand(ctx, actor, func(g *gameState) (struct{}, error) {
+		if g.started {
+			return struct{}{}, newError(ErrConflict, "a game in progress cannot be started again")
+		}
+
+		players := g.getPlayers()
+		if len(players) < g.config.MinPlayers {
+			return struct{}{}, &NotEnoughPlayersError{
+				PlayerCount: len(players),
+				MinPlayers:  g.config.MinPlayers,
+			}
+		}
+
+		// assign roles - by default, one mafia for every five players, rounded up
+		if err := g.config.assignRoles(players); err != nil {
+			return struct{}{}, fmt.Errorf("failed to assign roles: %w", err)
+		}
+
+		g.announceStart(ctx)
+
+		g.logger.InfoContext(ctx, "started game", "playerCount", len(players), "timeOfDay", g.currentPhase)
+
+		i.schedulePhaseExecution(actor, g)
+
+		return struct{}{}, nil
+	})
+	if err != nil {
+		return err
 	}
 
+	i.recordGameStarted()
+	i.notifyLobbyChanged()
+
 	return nil
 }
 
 func (i *InMemoryEngine) VoteToKill(ctx context.Context, hostAddress string, killerAddress string, killeeAddress string) error {
-	gameState, hasGameState := i.getGameState(hostAddress)
-	if !hasGameState {
-		return fmt.Errorf("no game found for host address '%s'", hostAddress)
+	return i.runGameAction(ctx, hostAddress, func(g *gameState) error {
+		if err := g.voteToKill(killerAddress, killeeAddress); err != nil {
+			return err
+		}
+
+		g.logger.DebugContext(ctx, "voted to kill player", "phase", g.getPhaseNumber()+1, "playerAddress", killerAddress, "victimAddress", killeeAddress)
+
+		return nil
+	})
+}
+
+func (i *InMemoryEngine) WaitForChatMessages(ctx context.Context, hostAddress string, readerAddress string, channel ChatChannel, afterSequence int) ([]*ChatMessage, error) {
+	// chatRead is what a single read of the chat finds
+	type chatRead struct {
+		messages []*ChatMessage
+		updated  <-chan struct{}
+	}
+
+	actor, hasActor := i.getGameActor(hostAddress)
+	if !hasActor {
+		return nil, newGameNotFoundError(hostAddress)
 	}
 
-	return gameState.voteToKill(killerAddress, killeeAddress)
+	for {
+		read, err := runCommand(ctx, actor, func(g *gameState) (*chatRead, error) {
+			messages, updated, err := g.getChatMessages(readerAddress, channel, afterSequence)
+			if err != nil {
+				return nil, err
+			}
+
+			return &chatRead{
+				messages: messages,
+				updated:  updated,
+			}, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(read.messages) > 0 {
+			return read.messages, nil
+		}
+
+		select {
+		case <-read.updated:
+		case <-i.shuttingDown:
+			return nil, newShuttingDownError()
+		case <-actor.stopped:
+			return nil, newGameEndedError()
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		}
+	}
 }
 
 func (i *InMemoryEngine) WaitForGameStart(ctx context.Context, hostAddress string) error {
-	game, hasGame := i.getGameState(hostAddress)
-	if !hasGame {
-		return errors.New("a game cannot be started without initialization")
+	actor, hasActor := i.getGameActor(hostAddress)
+	if !hasActor {
+		return newError(ErrNotFound, "a game cannot be started without initialization")
 	}
 
-	subChan, err := game.subscribeToStart()
+	startSub, err := runCommand(ctx, actor, func(g *gameState) (*subscription[struct{}], error) {
+		return g.subscribeToStart(ctx), nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to subscribe to game start: %w", err)
+		return err
 	}
 
-	select {
-	case <-subChan:
+	if startSub == nil {
+		// nothing to wait on
 		return nil
-	case <-ctx.Done():
-		return context.Cause(ctx)
+	}
+
+	_, err = startSub.wait(ctx, i.shuttingDown, actor.stopped)
+	return err
+}
+
+func (i *InMemoryEngine) WaitForLobbyUpdate(ctx context.Context, sinceVersion int) (*Lobby, error) {
+	for {
+		lobby, lobbyUpdated, err := i.getLobby(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if lobby.Version > sinceVersion {
+			return lobby, nil
+		}
+
+		select {
+		case <-lobbyUpdated:
+		case <-i.shuttingDown:
+			return nil, newShuttingDownError()
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		}
 	}
 }
 
 func (i *InMemoryEngine) WaitForPhaseExecution(ctx context.Context, hostAddress string) (*PhaseExecution, error) {
-	game, hasGame := i.getGameState(hostAddress)
-	if !hasGame {
-		return nil, fmt.Errorf("no game state found for host address '%s'", hostAddress)
+	actor, hasActor := i.getGameActor(hostAddress)
+	if !hasActor {
+		return nil, newGameNotFoundError(hostAddress)
+	}
+
+	phaseSub, err := runCommand(ctx, actor, func(g *gameState) (*subscription[*PhaseExecution], error) {
+		return g.subscribeToPhaseExecution(ctx), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return phaseSub.wait(ctx, i.shuttingDown, actor.stopped)
+}
+
+// endGame removes the game for the given host address from play and moves it to the archive
+func (i *InMemoryEngine) endGame(ctx context.Context, hostAddress string, cancelled bool) {
+	i.gameActorsMutex.Lock()
+	actor, hasActor := i.gameActors[hostAddress]
+	delete(i.gameActors, hostAddress)
+	i.gameActorsMutex.Unlock()
+
+	if !hasActor {
+		return
 	}
 
-	subChan, err := game.subscribeToPhaseExecution()
+	// the game must be archived even if the request ending it goes away
+	archiveCtx := context.WithoutCancel(ctx)
+	_, err := runCommand(archiveCtx, actor, func(g *gameState) (struct{}, error) {
+		g.logger.InfoContext(ctx, "ended game", "cancelled", cancelled, "phaseOutcome", g.getLastPhaseOutcome())
+
+		i.archiveGame(hostAddress, g, cancelled)
+		i.recordGameEnded(g, cancelled)
+
+		return struct{}{}, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to subscribe to phase execution: %w", err)
+		actor.state.logger.ErrorContext(ctx, "failed to archive game", "error", err)
 	}
 
-	select {
-	case phaseExecution := <-subChan:
+	// no more commands can reach the game now that it has been removed, other than those already on their way, which find that it has ended
+	actor.stop()
+
+	i.notifyLobbyChanged()
+}
+
+// executePhase tallies the votes of the current phase of the given game and notifies all subscribers of the outcome.
+// If the given phase number is not anyPhaseNumber, then the phase is only executed if the game has had exactly that many phases executed.
+// This returns nil if the phase was not executed due to a mismatch of phase numbers.
+func (i *InMemoryEngine) executePhase(ctx context.Context, actor *gameActor, expectedPhaseNumber int) (*PhaseExecution, error) {
+	return runCommand(ctx, actor, func(g *gameState) (*PhaseExecution, error) {
+		if expectedPhaseNumber != anyPhaseNumber && g.getPhaseNumber() != expectedPhaseNumber {
+			return nil, nil
+		}
+
+		if err := g.checkNotOver(); err != nil {
+			return nil, err
+		}
+
+		currentPhase := g.currentPhase
+
+		phaseExecution := &PhaseExecution{
+			HostAddress:  actor.hostAddress,
+			CurrentPhase: currentPhase,
+		}
+		switch currentPhase {
+		case TimeOfDayDay:
+			phaseExecution.ConvictedPlayers = g.tallyMafiaVotes()
+		case TimeOfDayNight:
+			phaseExecution.KilledPlayers = g.tallyKillVotes()
+		default:
+			return nil, fmt.Errorf("unhandled phase: %v", currentPhase)
+		}
+
+		phaseExecution.PhaseOutcome = g.calculatePhaseOutcome()
+
+		g.logger.InfoContext(ctx, "executed phase", "phase", g.getPhaseNumber()+1, "timeOfDay", currentPhase, "phaseOutcome", phaseExecution.PhaseOutcome, "convictedPlayers", phaseExecution.ConvictedPlayers, "killedPlayers", phaseExecution.KilledPlayers)
+
+		g.notifyOfPhaseExecution(ctx, phaseExecution)
+		i.recordPhaseExecution(currentPhase)
+
+		if phaseExecution.PhaseOutcome != PhaseOutcomeContinuation {
+			i.recordStats(g, phaseExecution.PhaseOutcome)
+		} else {
+			i.schedulePhaseExecution(actor, g)
+		}
+
 		return phaseExecution, nil
-	case <-ctx.Done():
-		return nil, context.Cause(ctx)
+	})
+}
+
+// schedulePhaseExecution schedules the automatic execution of the current phase of the given game, if the game is configured to execute it automatically.
+// If the phase is executed by other means before the scheduled time, then the scheduled execution does nothing.
+// This must be called by the game's actor.
+func (i *InMemoryEngine) schedulePhaseExecution(actor *gameActor, g *gameState) {
+	phaseDuration := g.config.getPhaseDuration(g.currentPhase)
+	if phaseDuration <= 0 {
+		return
 	}
+
+	phaseNumber := g.getPhaseNumber()
+	logger := g.logger
+	time.AfterFunc(phaseDuration, func() {
+		// phases are left as they are once the engine is shutting down, so that they can be snapshotted
+		if i.isShuttingDown() {
+			return
+		}
+
+		// a game that has ended in the meantime has nothing left to execute
+		if _, err := i.executePhase(context.Background(), actor, phaseNumber); err != nil && !errors.Is(err, ErrNotFound) {
+			logger.Error("failed to automatically execute phase", "phase", phaseNumber+1, "error", err)
+		}
+	})
 }
 
+// addGameActor starts running the given game for the given host address
+func (i *InMemoryEngine) addGameActor(hostAddress string, gameState *gameState) error {
+	i.gameActorsMutex.Lock()
+	defer i.gameActorsMutex.Unlock()
+
+	if _, hasGame := i.gameActors[hostAddress]; hasGame {
+		return newError(ErrConflict, "a game cannot be initialized twice")
+	}
+
+	i.gameActors[hostAddress] = newGameActor(hostAddress, gameState)
+
+	return nil
+}
+
+// anyPhaseNumber is used to execute a phase regardless of how many phases have already been executed
+const anyPhaseNumber = -1
+
+// gameState is the state of a single game.
+// It must only be read or changed by the actor running the game, other than the logger, config, and creation time, which never change once the game is initialized.
 type gameState struct {
-	started bool
+	// logger logs messages that identify the game
+	logger    *slog.Logger
+	config    *GameConfig
+	createdAt time.Time
+
+	started   bool
+	startedAt time.Time
 
 	players      map[string]*Player
-	playersMutex sync.RWMutex
+	currentPhase TimeOfDay
+
+	gameStart *broadcaster[struct{}]
 
-	currentPhase      TimeOfDay
-	currentPhaseMutex sync.RWMutex
+	phaseExecuted   *broadcaster[*PhaseExecution]
+	phaseExecutions []*PhaseExecution
 
-	gameStartSubs  []chan any
-	gameStartMutex sync.Mutex
+	mafiaAccusations  map[string]string
+	accusationHistory []map[string]string
 
-	phaseExecutionSubs  []chan *PhaseExecution
-	phaseExecutionMutex sync.RWMutex
+	killVotes map[string]string
 
-	mafiaAccusations      map[string]string
-	mafiaAccusationsMutex sync.RWMutex
+	chatMessages map[ChatChannel][]*ChatMessage
+	chatUpdated  chan struct{}
 
-	killVotes      map[string]string
-	killVotesMutex sync.RWMutex
+	spectators map[string]*Spectator
+
+	statsRecorded bool
 }
 
-func newGameState() *gameState {
+func newGameState(config *GameConfig, logger *slog.Logger) *gameState {
 	return &gameState{
+		logger:           logger,
+		config:           config,
+		currentPhase:     config.StartingPhase,
+		createdAt:        time.Now(),
+		gameStart:        newBroadcaster[struct{}](),
+		phaseExecuted:    newBroadcaster[*PhaseExecution](),
 		players:          make(map[string]*Player),
 		mafiaAccusations: make(map[string]string),
 		killVotes:        make(map[string]string),
+		chatMessages:     make(map[ChatChannel][]*ChatMessage),
+		chatUpdated:      make(chan struct{}),
+		spectators:       make(map[string]*Spectator),
 	}
 }
 
-func (g *gameState) announceStart() error {
-	g.gameStartMutex.Lock()
-	defer g.gameStartMutex.Unlock()
-
-	if g.started {
-		return errors.New("game cannot be started multiple times")
-	}
-
-	fmt.Printf("Notifying %d subscribers of game start\n", len(g.gameStartSubs))
+// announceStart marks the game as started and tells everyone waiting for it to start
+func (g *gameState) announceStart(ctx context.Context) {
+	g.started = true
+	g.startedAt = time.Now()
 
-	for _, subChan := range g.gameStartSubs {
-		subChan <- nil
-		close(subChan)
-	}
-
-	g.gameStartSubs = nil
+	subscriberCount := g.gameStart.publish(struct{}{})
 
-	return nil
+	g.logger.DebugContext(ctx, "notified subscribers of game start", "subscriberCount", subscriberCount)
 }
 
 func (g *gameState) accuseAsMafia(accuserAddress string, accuseeAddress string) error {
-	if g.getCurrentPhase() != TimeOfDayDay {
-		return errors.New("Mafia accusations can only be made during the day")
+	if g.currentPhase != TimeOfDayDay {
+		return newError(ErrInvalidPhase, "Mafia accusations can only be made during the day")
 	}
 
 	if accuserPlayer := g.getPlayer(accuserAddress); accuserPlayer == nil {
-		return fmt.Errorf("accuser '%s' must be a member of game", accuserAddress)
+		return newError(ErrForbidden, "accuser '%s' must be a member of game", accuserAddress)
 	} else if !accuserPlayer.CanAct() {
-		return fmt.Errorf("accuser '%s' must be able to take actions in the game", accuserAddress)
+		return newError(ErrForbidden, "accuser '%s' must be able to take actions in the game", accuserAddress)
 	}
 
 	if accuseePlayer := g.getPlayer(accuseeAddress); accuseePlayer == nil {
-		return fmt.Errorf("the accused '%s' must be a member of the game", accuseeAddress)
+		return newError(ErrInvalidTarget, "the accused '%s' must be a member of the game", accuseeAddress)
 	} else if !accuseePlayer.CanAct() {
-		return fmt.Errorf("the accused '%s' must be able to take actions in the game", accuseeAddress)
+		return newError(ErrInvalidTarget, "the accused '%s' must be able to take actions in the game", accuseeAddress)
 	}
 
-	g.mafiaAccusationsMutex.Lock()
-	defer g.mafiaAccusationsMutex.Unlock()
-
 	if _, hasAccusation := g.mafiaAccusations[accuserAddress]; hasAccusation {
-		return errors.New("a Mafia vote accusation cannot be made twice")
+		return newError(ErrConflict, "a Mafia vote accusation cannot be made twice")
 	}
 
 	g.mafiaAccusations[accuserAddress] = accuseeAddress
@@ -290,19 +622,22 @@ func (g *gameState) accuseAsMafia(accuserAddress string, accuseeAddress string)
 	return nil
 }
 
-func (g *gameState) addPlayer(player *Player) {
-	g.playersMutex.Lock()
-	defer g.playersMutex.Unlock()
+func (g *gameState) addPlayer(player *Player) error {
+	if g.config.MaxPlayers > 0 && len(g.players) >= g.config.MaxPlayers {
+		return &GameFullError{
+			MaxPlayers: g.config.MaxPlayers,
+		}
+	}
 
 	g.players[player.PlayerAddress] = player
+
+	return nil
 }
 
 func (g *gameState) calculatePhaseOutcome() PhaseOutcome {
-	players := g.getPlayers()
-
 	var mafiaPlayers []*Player
 	var civvies []*Player
-	for _, player := range players {
+	for _, player := range g.players {
 		if !player.CanAct() {
 			continue
 		}
@@ -324,10 +659,10 @@ func (g *gameState) calculatePhaseOutcome() PhaseOutcome {
 	return PhaseOutcomeContinuation
 }
 
-// findHighestVote finds the address in the given map that has singly accrued the highest number
-// of votes. If one address has a majority of the votes, this returns the address and a bool value of 'true'.
-// If there is no clear winner of the votes, this returns false for the bool value.
-func (g *gameState) findHighestVote(votes map[string]string) (string, bool) {
+// findHighestVotes finds the addresses in the given map that share the highest number of votes.
+// If one address has a majority of the votes, this returns only that address.
+// If no votes have been cast, this returns no addresses.
+func (g *gameState) findHighestVotes(votes map[string]string) []string {
 	voteCounts := make(map[string]int)
 	var highestVoteCount int
 	for _, vote := range votes {
@@ -345,32 +680,51 @@ func (g *gameState) findHighestVote(votes map[string]string) (string, bool) {
 		}
 	}
 
-	if len(winners) == 1 {
-		return winners[0], true
-	}
+	sort.Strings(winners)
 
-	return "", false
+	return winners
 }
 
-func (g *gameState) getCurrentPhase() TimeOfDay {
-	g.currentPhaseMutex.RLock()
-	defer g.currentPhaseMutex.RUnlock()
+// forfeit marks the given player as dead and discards any votes cast by or against the player in the current phase
+func (g *gameState) forfeit(playerAddress string) error {
+	player := g.getPlayer(playerAddress)
+	if player == nil {
+		return newError(ErrNotFound, "player '%s' is not a member of the game", playerAddress)
+	} else if !player.CanAct() {
+		return newError(ErrForbidden, "player '%s' is no longer able to take actions in the game and cannot forfeit", playerAddress)
+	}
+
+	player.Dead = true
+	player.Forfeited = true
+
+	g.removeVotesInvolving(playerAddress)
+
+	return nil
+}
 
-	return g.currentPhase
+// getPhaseExecutions gets every phase execution of the game; the executions themselves are never changed, so they can be shared
+func (g *gameState) getPhaseExecutions() []*PhaseExecution {
+	phaseExecutions := make([]*PhaseExecution, len(g.phaseExecutions))
+	copy(phaseExecutions, g.phaseExecutions)
+	return phaseExecutions
 }
 
-func (i *InMemoryEngine) getGameState(hostAddress string) (*gameState, bool) {
-	i.gameStatesMutex.RLock()
-	defer i.gameStatesMutex.RUnlock()
+// getLastPhaseOutcome gets the outcome of the most recently executed phase of the game, which is a continuation if no phase has been executed
+func (g *gameState) getLastPhaseOutcome() PhaseOutcome {
+	if len(g.phaseExecutions) == 0 {
+		return PhaseOutcomeContinuation
+	}
+	return g.phaseExecutions[len(g.phaseExecutions)-1].PhaseOutcome
+}
 
-	gameState, hasGameState := i.gameStates[hostAddress]
-	return gameState, hasGameState
+// getPhaseNumber gets the number of phases that have been executed in the game
+func (g *gameState) getPhaseNumber() int {
+	return len(g.phaseExecutions)
 }
 
+// getPlayer gets the given player of the game, or nil if there is no such player.
+// The player must not be handed outside of the game's actor; use copyPlayer for that.
 func (g *gameState) getPlayer(playerAddress string) *Player {
-	g.playersMutex.RLock()
-	defer g.playersMutex.RUnlock()
-
 	if player, hasPlayer := g.players[playerAddress]; hasPlayer {
 		return player
 	}
@@ -378,10 +732,9 @@ func (g *gameState) getPlayer(playerAddress string) *Player {
 	return nil
 }
 
+// getPlayers gets every player of the game.
+// The players must not be handed outside of the game's actor; use copyPlayers for that.
 func (g *gameState) getPlayers() []*Player {
-	g.playersMutex.RLock()
-	defer g.playersMutex.RUnlock()
-
 	players := make([]*Player, 0, len(g.players))
 	for _, player := range g.players {
 		players = append(players, player)
@@ -389,115 +742,135 @@ func (g *gameState) getPlayers() []*Player {
 	return players
 }
 
-func (g *gameState) notifyOfPhaseExecution(phaseExecution *PhaseExecution) {
+func (g *gameState) notifyOfPhaseExecution(ctx context.Context, phaseExecution *PhaseExecution) {
+	g.phaseExecutions = append(g.phaseExecutions, phaseExecution)
+
 	switch phaseExecution.CurrentPhase {
 	case TimeOfDayDay:
-		defer func() {
-			g.mafiaAccusationsMutex.Lock()
-			defer g.mafiaAccusationsMutex.Unlock()
-
-			g.mafiaAccusations = make(map[string]string)
-			g.currentPhase = TimeOfDayNight
-		}()
+		g.accusationHistory = append(g.accusationHistory, g.mafiaAccusations)
+		g.mafiaAccusations = make(map[string]string)
+		g.currentPhase = TimeOfDayNight
 	case TimeOfDayNight:
-		defer func() {
-			g.killVotesMutex.Lock()
-			defer g.killVotesMutex.Unlock()
-
-			g.killVotes = make(map[string]string)
-			g.currentPhase = TimeOfDayDay
-		}()
+		g.killVotes = make(map[string]string)
+		g.currentPhase = TimeOfDayDay
 	}
 
-	g.phaseExecutionMutex.Lock()
-	defer g.phaseExecutionMutex.Unlock()
+	g.publishPhaseExecution(ctx, phaseExecution)
+}
+
+// announceGameOver records the given execution, which ends the game outside of the execution of a phase, such as when a player forfeits.
+// Unlike notifyOfPhaseExecution, this leaves the current phase and its votes as they are.
+func (g *gameState) announceGameOver(ctx context.Context, phaseExecution *PhaseExecution) {
+	g.phaseExecutions = append(g.phaseExecutions, phaseExecution)
 
-	fmt.Printf("Notifying %d users of phase execution\n", len(g.phaseExecutionSubs))
+	g.publishPhaseExecution(ctx, phaseExecution)
+}
 
-	for _, phaseSub := range g.phaseExecutionSubs {
-		phaseSub <- phaseExecution
-		close(phaseSub)
+// checkNotOver fails with an error matching ErrInvalidPhase if the game has already been won
+func (g *gameState) checkNotOver() error {
+	if phaseOutcome := g.getLastPhaseOutcome(); phaseOutcome != PhaseOutcomeContinuation {
+		return newError(ErrInvalidPhase, "the game has already ended in a %s", phaseOutcome)
 	}
 
-	g.phaseExecutionSubs = nil
+	return nil
 }
 
-func (g *gameState) subscribeToPhaseExecution() (<-chan *PhaseExecution, error) {
-	g.phaseExecutionMutex.Lock()
-	defer g.phaseExecutionMutex.Unlock()
+// publishPhaseExecution sends the given phase execution to everyone waiting for the current phase to be executed
+func (g *gameState) publishPhaseExecution(ctx context.Context, phaseExecution *PhaseExecution) {
+	subscriberCount := g.phaseExecuted.publish(phaseExecution)
 
-	newSub := make(chan *PhaseExecution)
-	g.phaseExecutionSubs = append(g.phaseExecutionSubs, newSub)
+	g.logger.DebugContext(ctx, "notified subscribers of phase execution", "phase", len(g.phaseExecutions), "timeOfDay", phaseExecution.CurrentPhase, "subscriberCount", subscriberCount)
+}
 
-	fmt.Printf("%d users have subscribed for phase execution\n", len(g.phaseExecutionSubs))
+func (g *gameState) removePlayer(playerAddress string) error {
+	if _, hasPlayer := g.players[playerAddress]; !hasPlayer {
+		return newError(ErrNotFound, "player '%s' is not a member of the game", playerAddress)
+	}
 
-	return newSub, nil
+	delete(g.players, playerAddress)
+
+	return nil
 }
 
-func (g *gameState) subscribeToStart() (<-chan any, error) {
-	g.gameStartMutex.Lock()
-	defer g.gameStartMutex.Unlock()
+// subscribeToPhaseExecution subscribes to the execution of the current phase; the subscription must be waited on
+func (g *gameState) subscribeToPhaseExecution(ctx context.Context) *subscription[*PhaseExecution] {
+	phaseSub := g.phaseExecuted.subscribe()
+
+	g.logger.DebugContext(ctx, "subscribed to phase execution", "phase", len(g.phaseExecutions)+1, "subscriberCount", g.phaseExecuted.subscriberCount())
+
+	return phaseSub
+}
 
+// subscribeToStart subscribes to the start of the game; this returns nil if the game has already started.
+// The subscription must be waited on.
+func (g *gameState) subscribeToStart(ctx context.Context) *subscription[struct{}] {
 	if g.started {
-		return nil, errors.New("cannot subscribe to game start when it is already started")
+		return nil
 	}
 
-	newSub := make(chan any)
-	g.gameStartSubs = append(g.gameStartSubs, newSub)
+	startSub := g.gameStart.subscribe()
 
-	fmt.Printf("%d users have subscribed for game start\n", len(g.gameStartSubs))
+	g.logger.DebugContext(ctx, "subscribed to game start", "subscriberCount", g.gameStart.subscriberCount())
 
-	return newSub, nil
+	return startSub
 }
 
-func (g *gameState) tallyMafiaVotes() (string, bool) {
-	g.mafiaAccusationsMutex.RLock()
-	defer g.mafiaAccusationsMutex.RUnlock()
+// resolveVotes determines which of the players with the highest number of votes are to be eliminated according to the game's tie policy
+func (g *gameState) resolveVotes(votes map[string]string) []string {
+	winners := g.findHighestVotes(votes)
+	if len(winners) <= 1 {
+		return winners
+	}
 
-	convictedAddress, isConvicted := g.findHighestVote(g.mafiaAccusations)
-	if isConvicted {
+	switch g.config.TiePolicy {
+	case TiePolicyRandom:
+		return []string{winners[rand.Intn(len(winners))]}
+	case TiePolicyEliminateAll:
+		return winners
+	default:
+		return nil
+	}
+}
+
+func (g *gameState) tallyMafiaVotes() []string {
+	convictedAddresses := g.resolveVotes(g.mafiaAccusations)
+	for _, convictedAddress := range convictedAddresses {
 		g.getPlayer(convictedAddress).Convicted = true
 	}
 
-	return convictedAddress, isConvicted
+	return convictedAddresses
 }
 
-func (g *gameState) tallyKillVotes() (string, bool) {
-	g.killVotesMutex.RLock()
-	defer g.killVotesMutex.RUnlock()
-
-	killedAddress, isKilled := g.findHighestVote(g.killVotes)
-	if isKilled {
+func (g *gameState) tallyKillVotes() []string {
+	killedAddresses := g.resolveVotes(g.killVotes)
+	for _, killedAddress := range killedAddresses {
 		g.getPlayer(killedAddress).Dead = true
 	}
 
-	return killedAddress, isKilled
+	return killedAddresses
 }
 
 func (g *gameState) voteToKill(voterAddress string, victimAddress string) error {
-	if g.getCurrentPhase() != TimeOfDayNight {
-		return errors.New("Votes to kill can only be made during the night")
+	if g.currentPhase != TimeOfDayNight {
+		return newError(ErrInvalidPhase, "Votes to kill can only be made during the night")
 	}
 
 	if voterPlayer := g.getPlayer(voterAddress); voterPlayer == nil {
-		return errors.New("voter must be a member of game")
+		return newError(ErrForbidden, "voter must be a member of game")
 	} else if !voterPlayer.CanAct() {
-		return errors.New("voter must be able to take actions in the game")
+		return newError(ErrForbidden, "voter must be able to take actions in the game")
 	} else if voterPlayer.PlayerRole != PlayerRoleMafia {
-		return errors.New("only members of the Mafia can take actions in the game")
+		return newError(ErrForbidden, "only members of the Mafia can take actions in the game")
 	}
 
 	if victimPlayer := g.getPlayer(victimAddress); victimPlayer == nil {
-		return errors.New("the victim must be a member of the game")
+		return newError(ErrInvalidTarget, "the victim must be a member of the game")
 	} else if !victimPlayer.CanAct() {
-		return errors.New("the victim player must be able to take actions in the game")
+		return newError(ErrInvalidTarget, "the victim player must be able to take actions in the game")
 	}
 
-	g.killVotesMutex.Lock()
-	defer g.killVotesMutex.Unlock()
-
 	if _, hasKillVote := g.killVotes[voterAddress]; hasKillVote {
-		return errors.New("a vote to kill cannot be made twice")
+		return newError(ErrConflict, "a vote to kill cannot be made twice")
 	}
 
 	g.killVotes[voterAddress] = victimAddress
@@ -505,6 +878,40 @@ func (g *gameState) voteToKill(voterAddress string, victimAddress string) error
 	return nil
 }
 
+// removeVotesInvolving discards all votes cast by or against the given player in the current phase
+func (g *gameState) removeVotesInvolving(playerAddress string) {
+	removeVotesInvolving(g.mafiaAccusations, playerAddress)
+	removeVotesInvolving(g.killVotes, playerAddress)
+}
+
+// removeVotesInvolving removes all votes in the given map that were cast by or against the given player
+func removeVotesInvolving(votes map[string]string, playerAddress string) {
+	for voterAddress, candidateAddress := range votes {
+		if voterAddress == playerAddress || candidateAddress == playerAddress {
+			delete(votes, voterAddress)
+		}
+	}
+}
+
+// copyPlayer copies the given player, so that it can be handed outside of the game's actor; this returns nil if the given player is nil
+func copyPlayer(player *Player) *Player {
+	if player == nil {
+		return nil
+	}
+
+	playerCopy := *player
+	return &playerCopy
+}
+
+// copyPlayers copies each of the given players, so that they can be handed outside of the game's actor
+func copyPlayers(players []*Player) []*Player {
+	playersCopy := make([]*Player, len(players))
+	for playerIndex, player := range players {
+		playersCopy[playerIndex] = copyPlayer(player)
+	}
+	return playersCopy
+}
+
 func newPlayer(playerAddress string, playerNickname string) *Player {
 	return &Player{
 		PlayerAddress:  playerAddress,
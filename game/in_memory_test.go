@@ -0,0 +1,382 @@
+package game_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+var _ = Describe("InMemoryEngine", func() {
+	var ctx context.Context
+	var engine *game.InMemoryEngine
+
+	BeforeEach(func() {
+		var cancelFn context.CancelFunc
+		ctx, cancelFn = context.WithTimeout(context.Background(), 10*time.Second)
+		DeferCleanup(cancelFn)
+
+		// thousands of waiters would bury any failure in debug messages
+		engine = game.NewInMemoryGameEngine(slog.New(slog.NewTextHandler(GinkgoWriter, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	})
+
+	It("is not held up by thousands of waiters that have stopped waiting", func() {
+		const waiterCount = 5000
+		hostAddress := "stresshost"
+
+		Expect(engine.InitializeGame(ctx, hostAddress, nil)).To(Succeed(), "initializing the game should not fail")
+		for playerIndex := 0; playerIndex < 5; playerIndex++ {
+			playerAddress := fmt.Sprintf("player%04d", playerIndex+1)
+			Expect(engine.JoinGame(ctx, hostAddress, playerAddress, playerAddress)).To(Succeed(), "joining player '%s' to the game should not fail", playerAddress)
+		}
+
+		// timedOutWaiters starts the given number of waiters whose contexts end almost immediately, returning once they have all stopped waiting
+		timedOutWaiters := func(wait func(waitCtx context.Context) error) {
+			var waitGroup sync.WaitGroup
+			waitErrs := make(chan error, waiterCount)
+			for waiterIndex := 0; waiterIndex < waiterCount; waiterIndex++ {
+				waitGroup.Add(1)
+				go func() {
+					defer waitGroup.Done()
+
+					waitCtx, cancelWait := context.WithTimeout(ctx, time.Millisecond)
+					defer cancelWait()
+
+					waitErrs <- wait(waitCtx)
+				}()
+			}
+			waitGroup.Wait()
+			close(waitErrs)
+
+			for waitErr := range waitErrs {
+				Expect(waitErr).To(MatchError(context.DeadlineExceeded), "every waiter should have stopped waiting when its context ended")
+			}
+		}
+
+		timedOutWaiters(func(waitCtx context.Context) error {
+			return engine.WaitForGameStart(waitCtx, hostAddress)
+		})
+
+		startWaited := make(chan error, 1)
+		go func() {
+			startWaited <- engine.WaitForGameStart(ctx, hostAddress)
+		}()
+
+		// give the waiter time to start waiting
+		time.Sleep(250 * time.Millisecond)
+
+		started := make(chan error, 1)
+		go func() {
+			started <- engine.StartGame(ctx, hostAddress)
+		}()
+		Eventually(started).Should(Receive(BeNil()), "starting the game should not be held up by the waiters that stopped waiting")
+		Eventually(startWaited).Should(Receive(BeNil()), "the waiter still waiting should be told that the game started")
+
+		timedOutWaiters(func(waitCtx context.Context) error {
+			_, err := engine.WaitForPhaseExecution(waitCtx, hostAddress)
+			return err
+		})
+
+		phaseWaited := make(chan *game.PhaseExecution, 1)
+		go func() {
+			defer GinkgoRecover()
+			phaseExecution, err := engine.WaitForPhaseExecution(ctx, hostAddress)
+			Expect(err).ToNot(HaveOccurred(), "waiting for the phase to be executed should not fail")
+			phaseWaited <- phaseExecution
+		}()
+
+		// give the waiter time to start waiting
+		time.Sleep(250 * time.Millisecond)
+
+		executed := make(chan *game.PhaseExecution, 1)
+		go func() {
+			defer GinkgoRecover()
+			phaseExecution, err := engine.ExecutePhase(ctx, hostAddress)
+			Expect(err).ToNot(HaveOccurred(), "executing the phase should not fail")
+			executed <- phaseExecution
+		}()
+		Eventually(executed).Should(Receive(Not(BeNil())), "executing the phase should not be held up by the waiters that stopped waiting")
+		Eventually(phaseWaited).Should(Receive(HaveField("HostAddress", hostAddress)), "the waiter still waiting should be told that the phase was executed")
+	})
+
+	It("keeps every game consistent while its players act concurrently", func() {
+		const gameCount = 4
+		const playerCount = 8
+		// enough phases for every game to end, since each phase with votes kills or convicts a player
+		const maxPhases = 2 * playerCount
+
+		hostAddresses := make([]string, gameCount)
+		playerAddresses := make(map[string][]string, gameCount)
+		for gameIndex := range hostAddresses {
+			hostAddress := fmt.Sprintf("loadhost%d", gameIndex+1)
+			hostAddresses[gameIndex] = hostAddress
+
+			Expect(engine.InitializeGame(ctx, hostAddress, nil)).To(Succeed(), "initializing game '%s' should not fail", hostAddress)
+			for playerIndex := 0; playerIndex < playerCount; playerIndex++ {
+				playerAddress := fmt.Sprintf("%s-player%d", hostAddress, playerIndex+1)
+				playerAddresses[hostAddress] = append(playerAddresses[hostAddress], playerAddress)
+				Expect(engine.JoinGame(ctx, hostAddress, playerAddress, playerAddress)).To(Succeed(), "joining player '%s' to game '%s' should not fail", playerAddress, hostAddress)
+			}
+			Expect(engine.StartGame(ctx, hostAddress)).To(Succeed(), "starting game '%s' should not fail", hostAddress)
+		}
+
+		// expectEngineError verifies that the given error, if any, is one that the engine returns for an action that cannot be taken at the time
+		expectEngineError := func(err error, description string) {
+			// calls still in flight when the players are stopped are cancelled
+			if err == nil || errors.Is(err, context.Canceled) {
+				return
+			}
+			Expect(err).To(Or(
+				MatchError(game.ErrConflict),
+				MatchError(game.ErrForbidden),
+				MatchError(game.ErrInvalidPhase),
+				MatchError(game.ErrInvalidTarget),
+				MatchError(game.ErrNotFound),
+			), "%s should only fail because the action cannot be taken at the time", description)
+		}
+
+		actorsCtx, stopActors := context.WithCancel(ctx)
+		defer stopActors()
+
+		var actorsGroup sync.WaitGroup
+		for _, hostAddress := range hostAddresses {
+			players := playerAddresses[hostAddress]
+			for playerIndex, playerAddress := range players {
+				actorsGroup.Add(1)
+				go func(hostAddress string, playerAddress string, targetAddress string) {
+					defer GinkgoRecover()
+					defer actorsGroup.Done()
+
+					for actorsCtx.Err() == nil {
+						expectEngineError(engine.AccuseAsMafia(actorsCtx, hostAddress, playerAddress, targetAddress), "accusing a player")
+						expectEngineError(engine.VoteToKill(actorsCtx, hostAddress, playerAddress, targetAddress), "voting to kill a player")
+
+						_, err := engine.PostChatMessage(actorsCtx, hostAddress, playerAddress, game.ChatChannelPublic, "still here")
+						expectEngineError(err, "posting a chat message")
+
+						gamePlayers, err := engine.GetPlayers(actorsCtx, hostAddress)
+						expectEngineError(err, "getting the players")
+						for _, gamePlayer := range gamePlayers {
+							// reading the players races with the game unless they are copies
+							Expect(gamePlayer.Forfeited).To(BeFalse(), "no player should have left game '%s'", hostAddress)
+						}
+
+						_, err = engine.GetVotes(actorsCtx, hostAddress)
+						expectEngineError(err, "getting the votes")
+					}
+				}(hostAddress, playerAddress, players[(playerIndex+1)%len(players)])
+			}
+		}
+
+		actorsGroup.Add(1)
+		go func() {
+			defer GinkgoRecover()
+			defer actorsGroup.Done()
+
+			for actorsCtx.Err() == nil {
+				_, err := engine.GetLobby(actorsCtx)
+				expectEngineError(err, "getting the lobby")
+				_, err = engine.GetCensus(actorsCtx)
+				expectEngineError(err, "getting the census")
+				_, err = engine.Snapshot(actorsCtx)
+				expectEngineError(err, "taking a snapshot")
+			}
+		}()
+
+		var hostsGroup sync.WaitGroup
+		for _, hostAddress := range hostAddresses {
+			hostsGroup.Add(1)
+			go func(hostAddress string) {
+				defer GinkgoRecover()
+				defer hostsGroup.Done()
+
+				for phaseIndex := 0; phaseIndex < maxPhases; phaseIndex++ {
+					// give the players time to vote
+					time.Sleep(10 * time.Millisecond)
+
+					phaseExecution, err := engine.ExecutePhase(ctx, hostAddress)
+					Expect(err).ToNot(HaveOccurred(), "executing phase %d of game '%s' should not fail", phaseIndex+1, hostAddress)
+					if phaseExecution.PhaseOutcome != game.PhaseOutcomeContinuation {
+						break
+					}
+				}
+
+				Expect(engine.FinishGame(ctx, hostAddress)).To(Succeed(), "finishing game '%s' should not fail", hostAddress)
+			}(hostAddress)
+		}
+
+		hostsGroup.Wait()
+		stopActors()
+		actorsGroup.Wait()
+
+		archivedGames, err := engine.GetArchivedGames(ctx)
+		Expect(err).ToNot(HaveOccurred(), "getting the archived games should not fail")
+		Expect(archivedGames).To(HaveLen(gameCount), "every game should have been archived")
+		for _, archivedGame := range archivedGames {
+			Expect(archivedGame.Players).To(HaveLen(playerCount), "every player of game '%s' should have been archived", archivedGame.HostAddress)
+
+			removedFromPlay := 0
+			for _, archivedPlayer := range archivedGame.Players {
+				if archivedPlayer.Dead || archivedPlayer.Convicted {
+					removedFromPlay++
+				}
+			}
+
+			removedPlayers := 0
+			for _, phaseExecution := range archivedGame.PhaseExecutions {
+				removedPlayers += len(phaseExecution.ConvictedPlayers) + len(phaseExecution.KilledPlayers)
+			}
+			Expect(removedFromPlay).To(Equal(removedPlayers), "every player of game '%s' who was killed or convicted should have been so by exactly one phase", archivedGame.HostAddress)
+		}
+
+		snapshot, err := engine.Snapshot(ctx)
+		Expect(err).ToNot(HaveOccurred(), "taking a snapshot should not fail")
+		Expect(snapshot.Games).To(BeEmpty(), "no game should be left running")
+	})
+
+	It("ends the game without executing the phase when a forfeit decides it", func() {
+		hostAddress := "forfeithost"
+		Expect(engine.InitializeGame(ctx, hostAddress, nil)).To(Succeed(), "initializing the game should not fail")
+		for playerIndex := 0; playerIndex < 5; playerIndex++ {
+			playerAddress := fmt.Sprintf("player%04d", playerIndex+1)
+			Expect(engine.JoinGame(ctx, hostAddress, playerAddress, playerAddress)).To(Succeed(), "joining player '%s' to the game should not fail", playerAddress)
+		}
+		Expect(engine.StartGame(ctx, hostAddress)).To(Succeed(), "starting the game should not fail")
+
+		players, err := engine.GetPlayers(ctx, hostAddress)
+		Expect(err).ToNot(HaveOccurred(), "getting the players should not fail")
+
+		var mafiaAddress string
+		var civilianAddresses []string
+		for _, player := range players {
+			if player.PlayerRole == game.PlayerRoleMafia {
+				mafiaAddress = player.PlayerAddress
+			} else {
+				civilianAddresses = append(civilianAddresses, player.PlayerAddress)
+			}
+		}
+		Expect(mafiaAddress).ToNot(BeEmpty(), "the game should have a member of the Mafia")
+
+		Expect(engine.LeaveGame(ctx, hostAddress, hostAddress, civilianAddresses[0])).To(MatchError(game.ErrForbidden), "the host should not be able to remove a player from a game in progress")
+
+		Expect(engine.AccuseAsMafia(ctx, hostAddress, civilianAddresses[0], civilianAddresses[1])).To(Succeed(), "accusing a player should not fail")
+		Expect(engine.LeaveGame(ctx, hostAddress, mafiaAddress, mafiaAddress)).To(Succeed(), "the Mafia member should be able to forfeit")
+
+		votes, err := engine.GetVotes(ctx, hostAddress)
+		Expect(err).ToNot(HaveOccurred(), "getting the votes should not fail")
+		Expect(votes.MafiaAccusations).To(HaveKeyWithValue(civilianAddresses[0], civilianAddresses[1]), "the votes of the phase should be left as they were, since the phase was not executed")
+
+		Expect(engine.LeaveGame(ctx, hostAddress, civilianAddresses[0], civilianAddresses[0])).To(MatchError(game.ErrInvalidPhase), "forfeiting a game that has been won should fail")
+		_, err = engine.ExecutePhase(ctx, hostAddress)
+		Expect(err).To(MatchError(game.ErrInvalidPhase), "executing a phase of a game that has been won should fail")
+	})
+
+	It("waits for phases by their number", func() {
+		hostAddress := "phasenumberhost"
+		Expect(engine.InitializeGame(ctx, hostAddress, nil)).To(Succeed(), "initializing the game should not fail")
+		for playerIndex := 0; playerIndex < 5; playerIndex++ {
+			playerAddress := fmt.Sprintf("player%04d", playerIndex+1)
+			Expect(engine.JoinGame(ctx, hostAddress, playerAddress, playerAddress)).To(Succeed(), "joining player '%s' to the game should not fail", playerAddress)
+		}
+		Expect(engine.StartGame(ctx, hostAddress)).To(Succeed(), "starting the game should not fail")
+
+		firstExecution, err := engine.ExecutePhase(ctx, hostAddress)
+		Expect(err).ToNot(HaveOccurred(), "executing the first phase should not fail")
+
+		missedExecution, err := engine.WaitForPhaseNumber(ctx, hostAddress, 1)
+		Expect(err).ToNot(HaveOccurred(), "waiting for a phase that has already been executed should not fail")
+		Expect(missedExecution).To(Equal(firstExecution), "the execution of a phase that has already been executed should be returned immediately")
+
+		secondWaited := make(chan *game.PhaseExecution, 1)
+		go func() {
+			defer GinkgoRecover()
+			phaseExecution, err := engine.WaitForPhaseNumber(ctx, hostAddress, 2)
+			Expect(err).ToNot(HaveOccurred(), "waiting for the second phase should not fail")
+			secondWaited <- phaseExecution
+		}()
+
+		// give the waiter time to start waiting
+		time.Sleep(250 * time.Millisecond)
+		Expect(secondWaited).ToNot(Receive(), "the second phase should not be returned before it is executed")
+
+		secondExecution, err := engine.ExecutePhase(ctx, hostAddress)
+		Expect(err).ToNot(HaveOccurred(), "executing the second phase should not fail")
+		Eventually(secondWaited).Should(Receive(Equal(secondExecution)), "the waiter should be given the execution of the second phase")
+
+		_, err = engine.WaitForPhaseNumber(ctx, hostAddress, 0)
+		Expect(err).To(MatchError(game.ErrInvalidPhase), "phases should be numbered from 1")
+	})
+
+	It("releases waiters when their game ends", func() {
+		hostAddress := "endedhost"
+		Expect(engine.InitializeGame(ctx, hostAddress, nil)).To(Succeed(), "initializing the game should not fail")
+		Expect(engine.JoinGame(ctx, hostAddress, hostAddress, hostAddress)).To(Succeed(), "the host joining the game should not fail")
+
+		startWaited := make(chan error, 1)
+		go func() {
+			startWaited <- engine.WaitForGameStart(ctx, hostAddress)
+		}()
+		phaseWaited := make(chan error, 1)
+		go func() {
+			_, err := engine.WaitForPhaseNumber(ctx, hostAddress, 1)
+			phaseWaited <- err
+		}()
+		chatWaited := make(chan error, 1)
+		go func() {
+			_, err := engine.WaitForChatMessages(ctx, hostAddress, hostAddress, game.ChatChannelPublic, 0)
+			chatWaited <- err
+		}()
+
+		// give the waiters time to start waiting
+		time.Sleep(250 * time.Millisecond)
+
+		Expect(engine.CancelGame(ctx, hostAddress)).To(Succeed(), "cancelling the game should not fail")
+		Eventually(startWaited).Should(Receive(MatchError(game.ErrNotFound)), "the waiter for the start of the game should be told that the game is gone")
+		Eventually(phaseWaited).Should(Receive(MatchError(game.ErrNotFound)), "the waiter for the phase execution should be told that the game is gone")
+		Eventually(chatWaited).Should(Receive(MatchError(game.ErrNotFound)), "the waiter for chat messages should be told that the game is gone")
+	})
+
+	It("rejects malformed requests as invalid", func() {
+		hostAddress := "invalidhost"
+
+		invalidConfig := game.DefaultGameConfig()
+		invalidConfig.MinPlayers = 1
+		Expect(engine.InitializeGame(ctx, hostAddress, invalidConfig)).To(MatchError(game.ErrInvalidRequest), "an invalid configuration should be rejected as invalid")
+
+		Expect(engine.InitializeGame(ctx, hostAddress, nil)).To(Succeed(), "initializing the game should not fail")
+		for playerIndex := 0; playerIndex < 3; playerIndex++ {
+			playerAddress := fmt.Sprintf("player%04d", playerIndex+1)
+			Expect(engine.JoinGame(ctx, hostAddress, playerAddress, playerAddress)).To(Succeed(), "joining player '%s' to the game should not fail", playerAddress)
+		}
+		Expect(engine.StartGame(ctx, hostAddress)).To(Succeed(), "starting the game should not fail")
+
+		_, err := engine.PostChatMessage(ctx, hostAddress, "player0001", game.ChatChannelPublic, "")
+		Expect(err).To(MatchError(game.ErrInvalidRequest), "empty chat messages should be rejected as invalid")
+	})
+
+	It("releases waiters when shut down", func() {
+		hostAddress := "shutdownhost"
+		Expect(engine.InitializeGame(ctx, hostAddress, nil)).To(Succeed(), "initializing the game should not fail")
+
+		startWaited := make(chan error, 1)
+		go func() {
+			startWaited <- engine.WaitForGameStart(ctx, hostAddress)
+		}()
+
+		// give the waiter time to start waiting
+		time.Sleep(250 * time.Millisecond)
+
+		Expect(engine.Shutdown(ctx)).To(Succeed(), "shutting down should not fail")
+		Eventually(startWaited).Should(Receive(MatchError(game.ErrShuttingDown)), "the waiter should be told that the engine is shutting down")
+
+		_, err := engine.WaitForPhaseExecution(ctx, hostAddress)
+		Expect(err).To(MatchError(game.ErrShuttingDown), "waiters that come after the shutdown should be released immediately")
+	})
+})
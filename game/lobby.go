@@ -0,0 +1,88 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// Lobby is a listing of all games that can be joined
+type Lobby struct {
+	// Version is incremented every time the listing changes
+	Version int
+	Games   []*LobbyGame
+}
+
+// LobbyGame describes a public game that has been initialized but not yet started
+type LobbyGame struct {
+	HostAddress  string
+	HostNickname string
+	PlayerCount  int
+	MinPlayers   int
+	// Capacity is the maximum number of players that can join the game; zero means there is no limit
+	Capacity  int
+	CreatedAt time.Time
+}
+
+// getLobby builds the current lobby listing and returns a channel that is closed the next time the listing changes
+func (i *InMemoryEngine) getLobby(ctx context.Context) (*Lobby, <-chan struct{}, error) {
+	i.lobbyMutex.RLock()
+	lobby := &Lobby{
+		Version: i.lobbyVersion,
+		Games:   make([]*LobbyGame, 0),
+	}
+	lobbyUpdated := i.lobbyUpdated
+	i.lobbyMutex.RUnlock()
+
+	for _, actor := range i.getGameActors() {
+		lobbyGame, err := runCommand(ctx, actor, func(g *gameState) (*LobbyGame, error) {
+			return g.getLobbyGame(actor.hostAddress), nil
+		})
+		if errors.Is(err, ErrNotFound) {
+			// the game ended after the actors were listed
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		if lobbyGame != nil {
+			lobby.Games = append(lobby.Games, lobbyGame)
+		}
+	}
+
+	sort.Slice(lobby.Games, func(a, b int) bool {
+		return lobby.Games[a].CreatedAt.Before(lobby.Games[b].CreatedAt)
+	})
+
+	return lobby, lobbyUpdated, nil
+}
+
+// getLobbyGame describes the game in the lobby; this returns nil if the game cannot be joined and so is not listed
+func (g *gameState) getLobbyGame(hostAddress string) *LobbyGame {
+	if g.config.Private || g.started {
+		return nil
+	}
+
+	lobbyGame := &LobbyGame{
+		HostAddress: hostAddress,
+		PlayerCount: len(g.players),
+		MinPlayers:  g.config.MinPlayers,
+		Capacity:    g.config.MaxPlayers,
+		CreatedAt:   g.createdAt,
+	}
+	if host := g.getPlayer(hostAddress); host != nil {
+		lobbyGame.HostNickname = host.PlayerNickname
+	}
+	return lobbyGame
+}
+
+// notifyLobbyChanged signals to all lobby watchers that the listing of joinable games may have changed
+func (i *InMemoryEngine) notifyLobbyChanged() {
+	i.lobbyMutex.Lock()
+	defer i.lobbyMutex.Unlock()
+
+	i.lobbyVersion++
+	close(i.lobbyUpdated)
+	i.lobbyUpdated = make(chan struct{})
+}
@@ -0,0 +1,53 @@
+package game
+
+import "context"
+
+// PhaseEngine waits for the phases of a game by their number, so that a waiter who is busy when a phase is executed, such as a bot that is still voting, does not miss the execution
+type PhaseEngine interface {
+	// WaitForPhaseNumber waits for the given phase of the game, counting from 1, to be executed; if it already has been, then its execution is returned immediately.
+	// If the game ends before the phase is executed, then this fails with an error matching ErrNotFound.
+	WaitForPhaseNumber(ctx context.Context, hostAddress string, phaseNumber int) (*PhaseExecution, error)
+}
+
+func (i *InMemoryEngine) WaitForPhaseNumber(ctx context.Context, hostAddress string, phaseNumber int) (*PhaseExecution, error) {
+	if phaseNumber < 1 {
+		return nil, newError(ErrInvalidPhase, "phases are numbered from 1, not %d", phaseNumber)
+	}
+
+	actor, hasActor := i.getGameActor(hostAddress)
+	if !hasActor {
+		return nil, newGameNotFoundError(hostAddress)
+	}
+
+	// phaseRead is either the execution of the phase, if it has already happened, or a subscription to the next execution
+	type phaseRead struct {
+		phaseExecution *PhaseExecution
+		phaseSub       *subscription[*PhaseExecution]
+	}
+
+	for {
+		read, err := runCommand(ctx, actor, func(g *gameState) (*phaseRead, error) {
+			if g.getPhaseNumber() >= phaseNumber {
+				return &phaseRead{
+					phaseExecution: g.phaseExecutions[phaseNumber-1],
+				}, nil
+			}
+
+			return &phaseRead{
+				phaseSub: g.subscribeToPhaseExecution(ctx),
+			}, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if read.phaseExecution != nil {
+			return read.phaseExecution, nil
+		}
+
+		// an earlier phase may be the one executed, so look again once anything is
+		if _, err := read.phaseSub.wait(ctx, i.shuttingDown, actor.stopped); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// ShutdownEngine is an engine that can be stopped gracefully, such as when the server receives a signal to stop
+type ShutdownEngine interface {
+	// Shutdown releases every pending waiter with an error matching ErrShuttingDown, as well as every waiter that comes after.
+	// Phases are no longer executed automatically once the engine is shutting down. Shutting down more than once does nothing.
+	Shutdown(ctx context.Context) error
+	// Snapshot describes the state of every game hosted by the engine, so that it can be kept once the engine has stopped
+	Snapshot(ctx context.Context) (*Snapshot, error)
+}
+
+// Snapshot is the state of every game hosted by an engine at a point in time
+type Snapshot struct {
+	TakenAt time.Time
+	// Games are the games that have not been finished or cancelled, sorted by the address of their host
+	Games         []*GameSnapshot
+	ArchivedGames []*ArchivedGame
+}
+
+// GameSnapshot is the state of a game that has not been finished or cancelled
+type GameSnapshot struct {
+	HostAddress     string
+	Config          *GameConfig
+	Started         bool
+	CurrentPhase    TimeOfDay
+	Players         []*Player
+	PhaseExecutions []*PhaseExecution
+	// Votes are the votes cast in the current phase
+	Votes     *Votes
+	CreatedAt time.Time
+	StartedAt time.Time
+}
+
+func (i *InMemoryEngine) Shutdown(ctx context.Context) error {
+	i.shutdownOnce.Do(func() {
+		// every waiter waits on this as well, so closing it releases them all
+		close(i.shuttingDown)
+
+		i.gameActorsMutex.RLock()
+		activeGames := len(i.gameActors)
+		i.gameActorsMutex.RUnlock()
+
+		i.logger.InfoContext(ctx, "shut down game engine", "activeGames", activeGames)
+	})
+
+	return nil
+}
+
+func (i *InMemoryEngine) Snapshot(ctx context.Context) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		TakenAt: time.Now(),
+	}
+
+	for _, actor := range i.getGameActors() {
+		gameSnapshot, err := runCommand(ctx, actor, func(g *gameState) (*GameSnapshot, error) {
+			return g.snapshot(actor.hostAddress), nil
+		})
+		if errors.Is(err, ErrNotFound) {
+			// the game ended after the actors were listed, so it is in the archive instead
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		snapshot.Games = append(snapshot.Games, gameSnapshot)
+	}
+
+	sort.Slice(snapshot.Games, func(a, b int) bool {
+		return snapshot.Games[a].HostAddress < snapshot.Games[b].HostAddress
+	})
+
+	// read the archive last so that games that end while the snapshot is taken are not missed
+	snapshot.ArchivedGames = i.getArchivedGames()
+
+	return snapshot, nil
+}
+
+func (i *InMemoryEngine) isShuttingDown() bool {
+	select {
+	case <-i.shuttingDown:
+		return true
+	default:
+		return false
+	}
+}
+
+func (g *gameState) snapshot(hostAddress string) *GameSnapshot {
+	playersCopy := copyPlayers(g.getPlayers())
+	sort.Slice(playersCopy, func(a, b int) bool {
+		return playersCopy[a].PlayerAddress < playersCopy[b].PlayerAddress
+	})
+
+	configCopy := *g.config
+
+	return &GameSnapshot{
+		HostAddress:     hostAddress,
+		Config:          &configCopy,
+		Started:         g.started,
+		CurrentPhase:    g.currentPhase,
+		Players:         playersCopy,
+		PhaseExecutions: g.getPhaseExecutions(),
+		Votes:           g.getVotes(),
+		CreatedAt:       g.createdAt,
+		StartedAt:       g.startedAt,
+	}
+}
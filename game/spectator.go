@@ -0,0 +1,58 @@
+package game
+
+// Spectator is a non-player watching a game
+type Spectator struct {
+	SpectatorAddress string
+	// GodView spectators can see the roles of all players and the votes cast so far in the current phase: the Mafia accusations during the day and the votes to kill during the night
+	GodView bool
+}
+
+// Votes are the votes cast so far in the current phase of a game, keyed by the address of the voter
+type Votes struct {
+	MafiaAccusations map[string]string
+	KillVotes        map[string]string
+}
+
+func (g *gameState) addSpectator(spectator *Spectator) error {
+	if spectator.GodView && !g.config.AllowGodView {
+		return newError(ErrForbidden, "the host has not allowed spectators to see roles and votes")
+	}
+
+	if player := g.getPlayer(spectator.SpectatorAddress); player != nil {
+		return newError(ErrConflict, "'%s' is a player in the game and cannot also be a spectator", spectator.SpectatorAddress)
+	}
+
+	if _, hasSpectator := g.spectators[spectator.SpectatorAddress]; hasSpectator {
+		return newError(ErrConflict, "'%s' is already spectating the game", spectator.SpectatorAddress)
+	}
+
+	g.spectators[spectator.SpectatorAddress] = spectator
+
+	return nil
+}
+
+func (g *gameState) getSpectator(spectatorAddress string) *Spectator {
+	if spectator, hasSpectator := g.spectators[spectatorAddress]; hasSpectator {
+		spectatorCopy := *spectator
+		return &spectatorCopy
+	}
+
+	return nil
+}
+
+func (g *gameState) getVotes() *Votes {
+	votes := &Votes{
+		MafiaAccusations: make(map[string]string),
+		KillVotes:        make(map[string]string),
+	}
+
+	for accuserAddress, accusedAddress := range g.mafiaAccusations {
+		votes.MafiaAccusations[accuserAddress] = accusedAddress
+	}
+
+	for killerAddress, victimAddress := range g.killVotes {
+		votes.KillVotes[killerAddress] = victimAddress
+	}
+
+	return votes
+}
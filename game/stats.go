@@ -0,0 +1,89 @@
+package game
+
+// PlayerStats are the accumulated statistics of a player across all games that have reached a victory
+type PlayerStats struct {
+	PlayerAddress  string
+	GamesPlayed    int
+	CivilianWins   int
+	MafiaWins      int
+	TimesConvicted int
+	TimesKilled    int
+	// Accusations is the number of Mafia accusations made by the player during the day
+	Accusations int
+	// CorrectAccusations is the number of Mafia accusations made by the player against players who were actually in the Mafia
+	CorrectAccusations int
+}
+
+// recordStats records the statistics of all players in the given game, which has ended with the given outcome.
+// Statistics are only recorded once per game. This must be called by the game's actor.
+func (i *InMemoryEngine) recordStats(gameState *gameState, phaseOutcome PhaseOutcome) {
+	if gameState.statsRecorded {
+		return
+	}
+	gameState.statsRecorded = true
+
+	players := gameState.getPlayers()
+	roles := make(map[string]PlayerRole, len(players))
+	for _, player := range players {
+		roles[player.PlayerAddress] = player.PlayerRole
+	}
+
+	i.statsMutex.Lock()
+	defer i.statsMutex.Unlock()
+
+	for _, player := range players {
+		stats := i.getOrCreateStats(player.PlayerAddress)
+		stats.GamesPlayed++
+
+		switch {
+		case phaseOutcome == PhaseOutcomeCivilianVictory && player.PlayerRole == PlayerRoleCivilian:
+			stats.CivilianWins++
+		case phaseOutcome == PhaseOutcomeMafiaVictory && player.PlayerRole == PlayerRoleMafia:
+			stats.MafiaWins++
+		}
+
+		if player.Convicted {
+			stats.TimesConvicted++
+		}
+
+		if player.Dead && !player.Forfeited {
+			stats.TimesKilled++
+		}
+	}
+
+	for _, accusations := range gameState.accusationHistory {
+		for accuserAddress, accusedAddress := range accusations {
+			stats := i.getOrCreateStats(accuserAddress)
+			stats.Accusations++
+			if roles[accusedAddress] == PlayerRoleMafia {
+				stats.CorrectAccusations++
+			}
+		}
+	}
+}
+
+// getOrCreateStats gets the statistics for the given player, creating them if needed; the stats mutex must be held by the caller.
+func (i *InMemoryEngine) getOrCreateStats(playerAddress string) *PlayerStats {
+	stats, hasStats := i.playerStats[playerAddress]
+	if !hasStats {
+		stats = &PlayerStats{
+			PlayerAddress: playerAddress,
+		}
+		i.playerStats[playerAddress] = stats
+	}
+	return stats
+}
+
+func (i *InMemoryEngine) getPlayerStats(playerAddress string) *PlayerStats {
+	i.statsMutex.RLock()
+	defer i.statsMutex.RUnlock()
+
+	if stats, hasStats := i.playerStats[playerAddress]; hasStats {
+		statsCopy := *stats
+		return &statsCopy
+	}
+
+	return &PlayerStats{
+		PlayerAddress: playerAddress,
+	}
+}
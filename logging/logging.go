@@ -0,0 +1,66 @@
+// Package logging builds the structured loggers used throughout the server.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/jrh3k5/mafia-dapp-http/config"
+)
+
+var levels = map[config.LogLevel]slog.Level{
+	config.LogLevelDebug: slog.LevelDebug,
+	config.LogLevelInfo:  slog.LevelInfo,
+	config.LogLevelWarn:  slog.LevelWarn,
+	config.LogLevelError: slog.LevelError,
+}
+
+// NewLogger builds a logger that writes messages at or above the given level to the given output in the given format.
+// Every message logged with a context carrying attributes (see WithAttrs) includes those attributes.
+func NewLogger(output io.Writer, format config.LogFormat, level config.LogLevel) *slog.Logger {
+	options := &slog.HandlerOptions{
+		Level: levels[level],
+	}
+
+	var handler slog.Handler
+	switch format {
+	case config.LogFormatJSON:
+		handler = slog.NewJSONHandler(output, options)
+	default:
+		handler = slog.NewTextHandler(output, options)
+	}
+
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+type attrsContextKey struct{}
+
+// WithAttrs builds a context that adds the given attributes to every message logged with it, such as the ID of the request being handled
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existingAttrs, _ := ctx.Value(attrsContextKey{}).([]slog.Attr)
+	combinedAttrs := make([]slog.Attr, 0, len(existingAttrs)+len(attrs))
+	combinedAttrs = append(combinedAttrs, existingAttrs...)
+	combinedAttrs = append(combinedAttrs, attrs...)
+	return context.WithValue(ctx, attrsContextKey{}, combinedAttrs)
+}
+
+// contextHandler adds the attributes carried by the context of each message before handing it off
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs, hasAttrs := ctx.Value(attrsContextKey{}).([]slog.Attr); hasAttrs {
+		record.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
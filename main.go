@@ -1,15 +1,105 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
 	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/config"
+	"github.com/jrh3k5/mafia-dapp-http/logging"
 	"github.com/jrh3k5/mafia-dapp-http/server"
 )
 
+// snapshotTimeout is how long the snapshot of the games may take once requests have been drained, which may have used up all of the shutdown timeout
+const snapshotTimeout = 10 * time.Second
+
 func main() {
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	if cfg.LogLevel != config.LogLevelDebug {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	logger := logging.NewLogger(os.Stderr, cfg.LogFormat, cfg.LogLevel)
+
 	// initialize random seed for shuffling player assignments
 	rand.Seed(time.Now().UnixNano())
 
-	server.NewServer().Run("0.0.0.0:3000")
+	if err := run(cfg, logger); err != nil {
+		logger.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+// run serves requests until the process is told to stop, then shuts the server down gracefully
+func run(cfg *config.Config, logger *slog.Logger) error {
+	gameServer, err := server.NewServer(cfg, logger)
+	if err != nil {
+		return fmt.Errorf("failed to build server: %w", err)
+	}
+
+	httpServer := &http.Server{
+		Addr:    cfg.ListenAddress,
+		Handler: gameServer,
+	}
+	// release the long polls once no more requests are being accepted, so that they do not hold up the shutdown
+	httpServer.RegisterOnShutdown(func() {
+		if err := gameServer.Shutdown(context.Background()); err != nil {
+			logger.Error("failed to release long polls", "error", err)
+		}
+	})
+
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("listening for requests", "listenAddress", cfg.ListenAddress)
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-signalCtx.Done():
+	}
+
+	// a second signal stops the process immediately
+	stopSignals()
+
+	logger.Info("shutting down", "shutdownTimeout", cfg.ShutdownTimeout)
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelShutdown()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		// the snapshot is still worth taking even if some requests could not be drained
+		logger.Error("failed to drain requests", "error", err)
+	}
+
+	snapshotCtx, cancelSnapshot := context.WithTimeout(context.Background(), snapshotTimeout)
+	defer cancelSnapshot()
+
+	if err := gameServer.WriteSnapshot(snapshotCtx); err != nil {
+		return err
+	}
+
+	logger.Info("shut down")
+
+	return nil
 }
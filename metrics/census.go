@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// censusTimeout is the longest that a scrape waits for the engine to take its census
+const censusTimeout = 5 * time.Second
+
+var phaseOutcomeLabels = map[game.PhaseOutcome]string{
+	game.PhaseOutcomeContinuation:    "continuation",
+	game.PhaseOutcomeCivilianVictory: "civilian_victory",
+	game.PhaseOutcomeMafiaVictory:    "mafia_victory",
+}
+
+var timeOfDayLabels = map[game.TimeOfDay]string{
+	game.TimeOfDayDay:   "day",
+	game.TimeOfDayNight: "night",
+}
+
+// censusCollector reads the state of the games from the engine whenever the metrics are scraped.
+// Reading it at scrape time, rather than counting calls made through the server, includes phases executed automatically by the engine.
+type censusCollector struct {
+	censusEngine game.CensusEngine
+
+	activeGames     *prometheus.Desc
+	gamePlayers     *prometheus.Desc
+	gamesStarted    *prometheus.Desc
+	gamesFinished   *prometheus.Desc
+	phaseExecutions *prometheus.Desc
+	scrapeErrors    prometheus.Counter
+}
+
+func newCensusCollector(censusEngine game.CensusEngine) *censusCollector {
+	return &censusCollector{
+		censusEngine: censusEngine,
+		activeGames: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "active_games"),
+			"Number of games that have been initialized and not yet finished or cancelled.",
+			nil, nil,
+		),
+		gamePlayers: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "game_players"),
+			"Number of players in each active game, by the address of its host.",
+			[]string{"host_address"}, nil,
+		),
+		gamesStarted: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "games_started_total"),
+			"Number of games that have been started.",
+			nil, nil,
+		),
+		gamesFinished: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "games_finished_total"),
+			"Number of games that have ended, by the outcome of their last phase; cancelled games have an outcome of 'cancelled'.",
+			[]string{"outcome"}, nil,
+		),
+		phaseExecutions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "phase_executions_total"),
+			"Number of phases that have been executed, by the time of day that ended.",
+			[]string{"time_of_day"}, nil,
+		),
+		scrapeErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "census_errors_total",
+			Help:      "Number of times that the state of the games could not be read from the engine.",
+		}),
+	}
+}
+
+func (c *censusCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- c.activeGames
+	descs <- c.gamePlayers
+	descs <- c.gamesStarted
+	descs <- c.gamesFinished
+	descs <- c.phaseExecutions
+	c.scrapeErrors.Describe(descs)
+}
+
+func (c *censusCollector) Collect(metrics chan<- prometheus.Metric) {
+	defer c.scrapeErrors.Collect(metrics)
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), censusTimeout)
+	defer cancelFn()
+
+	census, err := c.censusEngine.GetCensus(ctx)
+	if err != nil {
+		c.scrapeErrors.Inc()
+		return
+	}
+
+	metrics <- prometheus.MustNewConstMetric(c.activeGames, prometheus.GaugeValue, float64(len(census.ActiveGames)))
+	for hostAddress, playerCount := range census.ActiveGames {
+		metrics <- prometheus.MustNewConstMetric(c.gamePlayers, prometheus.GaugeValue, float64(playerCount), hostAddress)
+	}
+
+	metrics <- prometheus.MustNewConstMetric(c.gamesStarted, prometheus.CounterValue, float64(census.GamesStarted))
+
+	// every outcome is always exported so that rates can be computed from zero
+	for phaseOutcome, label := range phaseOutcomeLabels {
+		metrics <- prometheus.MustNewConstMetric(c.gamesFinished, prometheus.CounterValue, float64(census.GamesFinished[phaseOutcome]), label)
+	}
+	metrics <- prometheus.MustNewConstMetric(c.gamesFinished, prometheus.CounterValue, float64(census.GamesCancelled), "cancelled")
+
+	for timeOfDay, label := range timeOfDayLabels {
+		metrics <- prometheus.MustNewConstMetric(c.phaseExecutions, prometheus.CounterValue, float64(census.PhaseExecutions[timeOfDay]), label)
+	}
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// instrumentedEngine counts the votes cast and the long polls waiting through the engine that it wraps
+type instrumentedEngine struct {
+	game.Engine
+	metrics *Metrics
+}
+
+func (i *instrumentedEngine) AccuseAsMafia(ctx context.Context, hostAddress string, accuserAddress string, accuseeAddress string) error {
+	if err := i.Engine.AccuseAsMafia(ctx, hostAddress, accuserAddress, accuseeAddress); err != nil {
+		return err
+	}
+
+	i.metrics.votes.WithLabelValues("accuse").Inc()
+	return nil
+}
+
+func (i *instrumentedEngine) VoteToKill(ctx context.Context, hostAddress string, killerAddress string, killeeAddress string) error {
+	if err := i.Engine.VoteToKill(ctx, hostAddress, killerAddress, killeeAddress); err != nil {
+		return err
+	}
+
+	i.metrics.votes.WithLabelValues("kill").Inc()
+	return nil
+}
+
+func (i *instrumentedEngine) WaitForChatMessages(ctx context.Context, hostAddress string, readerAddress string, channel game.ChatChannel, afterSequence int) ([]*game.ChatMessage, error) {
+	defer i.trackWaiter("chat")()
+
+	return i.Engine.WaitForChatMessages(ctx, hostAddress, readerAddress, channel, afterSequence)
+}
+
+func (i *instrumentedEngine) WaitForGameStart(ctx context.Context, hostAddress string) error {
+	defer i.trackWaiter("start")()
+
+	return i.Engine.WaitForGameStart(ctx, hostAddress)
+}
+
+func (i *instrumentedEngine) WaitForLobbyUpdate(ctx context.Context, sinceVersion int) (*game.Lobby, error) {
+	defer i.trackWaiter("lobby")()
+
+	return i.Engine.WaitForLobbyUpdate(ctx, sinceVersion)
+}
+
+func (i *instrumentedEngine) WaitForPhaseExecution(ctx context.Context, hostAddress string) (*game.PhaseExecution, error) {
+	defer i.trackWaiter("phase")()
+
+	return i.Engine.WaitForPhaseExecution(ctx, hostAddress)
+}
+
+// trackWaiter counts a waiter for the given event and returns a function that stops counting it once it is done waiting
+func (i *instrumentedEngine) trackWaiter(wait string) func() {
+	waiters := i.metrics.waiters.WithLabelValues(wait)
+	waiters.Inc()
+	return waiters.Dec
+}
@@ -0,0 +1,97 @@
+// Package metrics exports Prometheus metrics describing the games being played and the requests being served.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "mafia"
+
+// Metrics holds all of the metrics exported by a server.
+// Each server has its own registry, so that several servers can run within the same process.
+type Metrics struct {
+	registry        *prometheus.Registry
+	votes           *prometheus.CounterVec
+	waiters         *prometheus.GaugeVec
+	requestDuration *prometheus.HistogramVec
+}
+
+// New builds the metrics of a server whose games are described by the given engine
+func New(censusEngine game.CensusEngine) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		votes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "votes_total",
+			Help:      "Number of votes successfully cast, by action (accuse or kill).",
+		}, []string{"action"}),
+		waiters: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "waiters",
+			Help:      "Number of long polls currently blocked waiting for something to happen, by what they are waiting for.",
+		}, []string{"wait"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time taken to respond to requests, by method, route, and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+	}
+
+	// export every label up front so that the series exist before anything happens
+	for _, action := range []string{"accuse", "kill"} {
+		m.votes.WithLabelValues(action)
+	}
+	for _, wait := range []string{"chat", "lobby", "phase", "start"} {
+		m.waiters.WithLabelValues(wait)
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		newCensusCollector(censusEngine),
+		m.votes,
+		m.waiters,
+		m.requestDuration,
+	)
+
+	return m
+}
+
+// Handler builds the handler that serves the metrics to Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// InstrumentEngine wraps the given engine so that the votes cast and the long polls waiting through it are counted
+func (m *Metrics) InstrumentEngine(engine game.Engine) game.Engine {
+	return &instrumentedEngine{
+		Engine:  engine,
+		metrics: m,
+	}
+}
+
+// Middleware builds a handler that records how long it takes to respond to each request.
+// Requests are labelled by their route pattern rather than their path, so that the number of series stays bounded.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestStart := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		m.requestDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Observe(time.Since(requestStart).Seconds())
+	}
+}
@@ -0,0 +1,153 @@
+// Package openapi describes the HTTP API of the server with an OpenAPI 3 document and validates requests and responses against it.
+package openapi
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed openapi.yaml
+var specification []byte
+
+//go:embed docs.html
+var docsPage []byte
+
+// Document is the OpenAPI document describing every route of the server
+type Document struct {
+	router routers.Router
+	json   []byte
+}
+
+// Load parses and validates the OpenAPI document embedded in the server
+func Load() (*Document, error) {
+	loader := openapi3.NewLoader()
+	parsedSpecification, err := loader.LoadFromData(specification)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+
+	if err := parsedSpecification.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+
+	router, err := gorillamux.NewRouter(parsedSpecification)
+	if err != nil {
+		return nil, fmt.Errorf("failed to route requests by the OpenAPI document: %w", err)
+	}
+
+	specificationJSON, err := parsedSpecification.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal OpenAPI document to JSON: %w", err)
+	}
+
+	return &Document{
+		router: router,
+		json:   specificationJSON,
+	}, nil
+}
+
+// DocsPage gets an HTML page that renders the OpenAPI document served at /openapi.json
+func DocsPage() []byte {
+	return docsPage
+}
+
+// JSON gets the OpenAPI document as JSON
+func (d *Document) JSON() []byte {
+	return d.json
+}
+
+// Middleware builds a handler that rejects requests that do not match the OpenAPI document before they are handled.
+// Requests that match no route of the server are left for the server to reject.
+func (d *Document) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// preflight requests are answered for every route, but are not part of the API itself
+		if c.FullPath() == "" || c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		requestValidationInput, err := d.findRoute(c.Request)
+		if err != nil {
+			// every route of the server should be described, so this is a bug rather than a bad request
+			_ = c.Error(err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"code": "internal_error", "message": "the route is not described by the OpenAPI document"})
+			return
+		}
+
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), requestValidationInput); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"code": "invalid_request", "message": describeValidationError(err)})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ValidateResponse determines whether the given response to the given request matches the OpenAPI document
+func (d *Document) ValidateResponse(request *http.Request, status int, header http.Header, body []byte) error {
+	requestValidationInput, err := d.findRoute(request)
+	if err != nil {
+		return err
+	}
+
+	return openapi3filter.ValidateResponse(request.Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: requestValidationInput,
+		Status:                 status,
+		Header:                 header,
+		Body:                   io.NopCloser(bytes.NewReader(body)),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+		},
+	})
+}
+
+func (d *Document) findRoute(request *http.Request) (*openapi3filter.RequestValidationInput, error) {
+	route, pathParams, err := d.router.FindRoute(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s %s in the OpenAPI document: %w", request.Method, request.URL.Path, err)
+	}
+
+	return &openapi3filter.RequestValidationInput{
+		Request:    request,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}, nil
+}
+
+// describeValidationError describes why a request is invalid without dumping the schema that it failed to match
+func describeValidationError(err error) string {
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return err.Error()
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(requestErr.Err, &schemaErr) {
+		reason := schemaErr.Reason
+		if fieldPath := schemaErr.JSONPointer(); len(fieldPath) > 0 {
+			reason = fmt.Sprintf("%s: %s", strings.Join(fieldPath, "."), reason)
+		}
+
+		if requestErr.Parameter != nil {
+			return fmt.Sprintf("parameter '%s' in %s is invalid: %s", requestErr.Parameter.Name, requestErr.Parameter.In, reason)
+		}
+		return "request body is invalid: " + reason
+	}
+
+	return requestErr.Error()
+}
@@ -0,0 +1,164 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+)
+
+// Driver is the means by which a scenario is played; game.Engine satisfies this, as does the client of a running server
+type Driver interface {
+	AccuseAsMafia(ctx context.Context, hostAddress string, accuserAddress string, accuseeAddress string) error
+	ExecutePhase(ctx context.Context, hostAddress string) (*game.PhaseExecution, error)
+	FinishGame(ctx context.Context, hostAddress string) error
+	InitializeGame(ctx context.Context, hostAddress string, config *game.GameConfig) error
+	JoinGame(ctx context.Context, hostAddress string, playerAddress string, playerNickname string) error
+	StartGame(ctx context.Context, hostAddress string) error
+	VoteToKill(ctx context.Context, hostAddress string, killerAddress string, killeeAddress string) error
+}
+
+// Mismatch is a difference between what a scenario expected and what actually happened
+type Mismatch struct {
+	// Step is the 1-based index of the step at which the mismatch occurred
+	Step    int
+	Message string
+}
+
+func (m *Mismatch) String() string {
+	return fmt.Sprintf("step %d: %s", m.Step, m.Message)
+}
+
+// Run plays the given scenario and reports every way in which the results differed from its expectations.
+// An error is returned if the scenario could not be played to completion, such as if a vote was rejected.
+// The game is finished once the scenario has been played, regardless of its outcome.
+func Run(ctx context.Context, driver Driver, scenario *Scenario) (mismatches []*Mismatch, err error) {
+	hostAddress := scenario.HostAddress()
+
+	if err := driver.InitializeGame(ctx, hostAddress, scenario.GameConfig()); err != nil {
+		return nil, fmt.Errorf("failed to initialize game: %w", err)
+	}
+
+	defer func() {
+		if finishErr := driver.FinishGame(ctx, hostAddress); finishErr != nil && err == nil {
+			err = fmt.Errorf("failed to finish game: %w", finishErr)
+		}
+	}()
+
+	for _, player := range scenario.Players {
+		nickname := player.Nickname
+		if nickname == "" {
+			nickname = player.Address
+		}
+
+		if err := driver.JoinGame(ctx, hostAddress, player.Address, nickname); err != nil {
+			return nil, fmt.Errorf("failed to join '%s' to the game: %w", player.Address, err)
+		}
+	}
+
+	if err := driver.StartGame(ctx, hostAddress); err != nil {
+		return nil, fmt.Errorf("failed to start game: %w", err)
+	}
+
+	for stepIndex, step := range scenario.Steps {
+		stepNumber := stepIndex + 1
+		switch {
+		case step.Day != nil:
+			for _, accuserAddress := range sortedKeys(step.Day) {
+				if err := driver.AccuseAsMafia(ctx, hostAddress, accuserAddress, step.Day[accuserAddress]); err != nil {
+					return mismatches, fmt.Errorf("step %d: '%s' failed to accuse '%s': %w", stepNumber, accuserAddress, step.Day[accuserAddress], err)
+				}
+			}
+		case step.Night != nil:
+			for _, killerAddress := range sortedKeys(step.Night) {
+				if err := driver.VoteToKill(ctx, hostAddress, killerAddress, step.Night[killerAddress]); err != nil {
+					return mismatches, fmt.Errorf("step %d: '%s' failed to vote to kill '%s': %w", stepNumber, killerAddress, step.Night[killerAddress], err)
+				}
+			}
+		case step.Execute != nil:
+			phaseExecution, err := driver.ExecutePhase(ctx, hostAddress)
+			if err != nil {
+				return mismatches, fmt.Errorf("step %d: failed to execute phase: %w", stepNumber, err)
+			}
+
+			for _, message := range step.Execute.check(phaseExecution) {
+				mismatches = append(mismatches, &Mismatch{
+					Step:    stepNumber,
+					Message: message,
+				})
+			}
+		}
+	}
+
+	return mismatches, nil
+}
+
+// check compares the given phase execution to the expectation, describing each difference
+func (e *Expectation) check(phaseExecution *game.PhaseExecution) []string {
+	var messages []string
+
+	if e.Convicted != nil && !sameAddresses(*e.Convicted, phaseExecution.ConvictedPlayers) {
+		messages = append(messages, fmt.Sprintf("expected convicted %s but got %s", formatAddresses(*e.Convicted), formatAddresses(phaseExecution.ConvictedPlayers)))
+	}
+
+	if e.Killed != nil && !sameAddresses(*e.Killed, phaseExecution.KilledPlayers) {
+		messages = append(messages, fmt.Sprintf("expected killed %s but got %s", formatAddresses(*e.Killed), formatAddresses(phaseExecution.KilledPlayers)))
+	}
+
+	if e.Outcome != "" {
+		// the outcome was validated when the scenario was parsed
+		if expectedOutcome, _ := parseOutcome(e.Outcome); expectedOutcome != phaseExecution.PhaseOutcome {
+			messages = append(messages, fmt.Sprintf("expected outcome %s but got %s", e.Outcome, formatOutcome(phaseExecution.PhaseOutcome)))
+		}
+	}
+
+	return messages
+}
+
+func formatAddresses(addresses []string) string {
+	return "[" + strings.Join(addresses, ", ") + "]"
+}
+
+func formatOutcome(outcome game.PhaseOutcome) string {
+	switch outcome {
+	case game.PhaseOutcomeContinuation:
+		return "continuation"
+	case game.PhaseOutcomeCivilianVictory:
+		return "civilian"
+	case game.PhaseOutcomeMafiaVictory:
+		return "mafia"
+	default:
+		return fmt.Sprintf("unknown (%d)", outcome)
+	}
+}
+
+// sameAddresses determines whether the given lists hold the same addresses, in any order
+func sameAddresses(expected []string, actual []string) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+
+	sortedExpected := append([]string(nil), expected...)
+	sort.Strings(sortedExpected)
+	sortedActual := append([]string(nil), actual...)
+	sort.Strings(sortedActual)
+
+	for addressIndex := range sortedExpected {
+		if sortedExpected[addressIndex] != sortedActual[addressIndex] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortedKeys(votes map[string]string) []string {
+	keys := make([]string, 0, len(votes))
+	for key := range votes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,92 @@
+package scenario_test
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+	"github.com/jrh3k5/mafia-dapp-http/scenario"
+)
+
+var _ = Describe("Run", func() {
+	var ctx context.Context
+	var engine *game.InMemoryEngine
+
+	BeforeEach(func() {
+		var cancelFn context.CancelFunc
+		ctx, cancelFn = context.WithTimeout(context.Background(), 10*time.Second)
+		DeferCleanup(cancelFn)
+
+		engine = game.NewInMemoryGameEngine(slog.New(slog.NewTextHandler(GinkgoWriter, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	})
+
+	// the Mafia member kills player2 on the first night, which leaves the Mafia outnumbered, so the game continues
+	const scenarioPrefix = `
+config:
+  startingPhase: 1
+players:
+  - address: player1
+    role: mafia
+  - address: player2
+    role: civilian
+  - address: player3
+    role: civilian
+  - address: player4
+    role: civilian
+  - address: player5
+    role: civilian
+steps:
+  - night: {player1: player2}
+`
+
+	DescribeTable("reports how the results of each phase differ from the expectations",
+		func(executeStep string, expectedMessages []string) {
+			parsedScenario, err := scenario.Parse([]byte(scenarioPrefix + executeStep))
+			Expect(err).ToNot(HaveOccurred(), "parsing the scenario should not fail")
+
+			mismatches, err := scenario.Run(ctx, engine, parsedScenario)
+			Expect(err).ToNot(HaveOccurred(), "running the scenario should not fail")
+
+			var messages []string
+			for _, mismatch := range mismatches {
+				Expect(mismatch.Step).To(Equal(2), "the mismatch should be reported at the execute step")
+				messages = append(messages, mismatch.Message)
+			}
+			Expect(messages).To(Equal(expectedMessages), "unexpected mismatches")
+		},
+		Entry("met expectations", "  - execute: {convicted: [], killed: [player2], outcome: continuation}\n", nil),
+		Entry("no expectations", "  - execute\n", nil),
+		Entry("wrong conviction", "  - execute: {convicted: [player2]}\n", []string{"expected convicted [player2] but got []"}),
+		Entry("wrong kill", "  - execute: {killed: [player3]}\n", []string{"expected killed [player3] but got [player2]"}),
+		Entry("wrong outcome", "  - execute: {outcome: mafia}\n", []string{"expected outcome mafia but got continuation"}),
+		Entry("every expectation wrong", "  - execute: {convicted: [player1], killed: [], outcome: civilian}\n", []string{
+			"expected convicted [player1] but got []",
+			"expected killed [] but got [player2]",
+			"expected outcome civilian but got continuation",
+		}),
+	)
+
+	It("fails when a step cannot be played", func() {
+		parsedScenario, err := scenario.Parse([]byte(scenarioPrefix + "  - night: {player2: player3}\n"))
+		Expect(err).ToNot(HaveOccurred(), "parsing the scenario should not fail")
+
+		_, err = scenario.Run(ctx, engine, parsedScenario)
+		Expect(err).To(MatchError(ContainSubstring("step 2: 'player2' failed to vote to kill 'player3'")), "a civilian voting to kill should fail the scenario")
+	})
+
+	It("finishes the game once the scenario has been played", func() {
+		parsedScenario, err := scenario.Parse([]byte(scenarioPrefix + "  - execute\n"))
+		Expect(err).ToNot(HaveOccurred(), "parsing the scenario should not fail")
+
+		_, err = scenario.Run(ctx, engine, parsedScenario)
+		Expect(err).ToNot(HaveOccurred(), "running the scenario should not fail")
+
+		archivedGames, err := engine.GetArchivedGames(ctx)
+		Expect(err).ToNot(HaveOccurred(), "getting the archived games should not fail")
+		Expect(archivedGames).To(HaveLen(1), "the game should have been finished and archived")
+	})
+})
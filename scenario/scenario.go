@@ -0,0 +1,239 @@
+package scenario
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is a scripted game: the players who play it and the steps that they take
+type Scenario struct {
+	Name string `yaml:"name"`
+	// Host is the address of the host of the game; if blank, the first player hosts the game
+	Host    string    `yaml:"host"`
+	Config  *Config   `yaml:"config"`
+	Players []*Player `yaml:"players"`
+	Steps   []*Step   `yaml:"steps"`
+}
+
+// Config holds the game configuration settings that can be set by a scenario.
+// These use the same names as the game configuration document accepted by the server.
+type Config struct {
+	MinPlayers        int    `yaml:"minPlayers"`
+	MaxPlayers        int    `yaml:"maxPlayers"`
+	MafiaCount        int    `yaml:"mafiaCount"`
+	MafiaRatio        int    `yaml:"mafiaRatio"`
+	TiePolicy         string `yaml:"tiePolicy"`
+	StartingPhase     int    `yaml:"startingPhase"`
+	RevealRoleOnDeath bool   `yaml:"revealRoleOnDeath"`
+}
+
+// Player is a player in a scenario
+type Player struct {
+	Address string `yaml:"address"`
+	// Nickname is the nickname with which the player joins the game; if blank, the address is used
+	Nickname string `yaml:"nickname"`
+	// Role is the role forced upon the player - civilian or mafia; if blank, the role is assigned at random
+	Role string `yaml:"role"`
+}
+
+// Step is a single step of a scenario; exactly one of its fields is set
+type Step struct {
+	// Day maps the addresses of accusers to the addresses of the players they accuse of being in the Mafia
+	Day map[string]string
+	// Night maps the addresses of Mafia members to the addresses of the players they vote to kill
+	Night map[string]string
+	// Execute, if set, executes the current phase and checks its results against the expectation
+	Execute *Expectation
+}
+
+// Expectation describes the expected results of executing a phase; any unset field is not checked
+type Expectation struct {
+	Convicted *[]string `yaml:"convicted"`
+	Killed    *[]string `yaml:"killed"`
+	// Outcome is one of continuation, civilian, or mafia
+	Outcome string `yaml:"outcome"`
+}
+
+// Load reads a scenario from the YAML or JSON file at the given path
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario file: %w", err)
+	}
+
+	scenario, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse scenario file '%s': %w", path, err)
+	}
+
+	return scenario, nil
+}
+
+// Parse reads a scenario from the given YAML or JSON document
+func Parse(data []byte) (*Scenario, error) {
+	defaultConfig := game.DefaultGameConfig()
+	scenario := &Scenario{
+		Config: &Config{
+			MinPlayers:    defaultConfig.MinPlayers,
+			MafiaRatio:    defaultConfig.MafiaRatio,
+			TiePolicy:     string(defaultConfig.TiePolicy),
+			StartingPhase: int(defaultConfig.StartingPhase),
+		},
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	// reject typos rather than silently ignoring steps
+	decoder.KnownFields(true)
+	if err := decoder.Decode(scenario); err != nil {
+		return nil, err
+	}
+
+	if err := scenario.validate(); err != nil {
+		return nil, err
+	}
+
+	return scenario, nil
+}
+
+// GameConfig builds the configuration of the game played in the scenario
+func (s *Scenario) GameConfig() *game.GameConfig {
+	config := game.DefaultGameConfig()
+	config.MinPlayers = s.Config.MinPlayers
+	config.MaxPlayers = s.Config.MaxPlayers
+	config.MafiaCount = s.Config.MafiaCount
+	config.MafiaRatio = s.Config.MafiaRatio
+	config.TiePolicy = game.TiePolicy(s.Config.TiePolicy)
+	config.StartingPhase = game.TimeOfDay(s.Config.StartingPhase)
+	config.RevealRoleOnDeath = s.Config.RevealRoleOnDeath
+
+	for _, player := range s.Players {
+		if player.Role == "" {
+			continue
+		}
+
+		if config.ForcedRoles == nil {
+			config.ForcedRoles = make(map[string]game.PlayerRole)
+		}
+		config.ForcedRoles[player.Address], _ = parseRole(player.Role)
+	}
+
+	return config
+}
+
+// HostAddress gets the address of the host of the game played in the scenario
+func (s *Scenario) HostAddress() string {
+	if s.Host != "" || len(s.Players) == 0 {
+		return s.Host
+	}
+
+	return s.Players[0].Address
+}
+
+func (s *Scenario) validate() error {
+	if s.Config == nil {
+		return errors.New("the game configuration of a scenario cannot be null")
+	}
+
+	if len(s.Players) == 0 {
+		return errors.New("a scenario must have players")
+	}
+
+	playerAddresses := make(map[string]bool, len(s.Players))
+	for playerIndex, player := range s.Players {
+		if player.Address == "" {
+			return fmt.Errorf("player %d must have an address", playerIndex+1)
+		} else if playerAddresses[player.Address] {
+			return fmt.Errorf("player '%s' is listed more than once", player.Address)
+		}
+		playerAddresses[player.Address] = true
+
+		if player.Role != "" {
+			if _, err := parseRole(player.Role); err != nil {
+				return fmt.Errorf("invalid role for player '%s': %w", player.Address, err)
+			}
+		}
+	}
+
+	for stepIndex, step := range s.Steps {
+		if step.Day == nil && step.Night == nil && step.Execute == nil {
+			return fmt.Errorf("step %d does nothing", stepIndex+1)
+		}
+
+		if step.Execute != nil && step.Execute.Outcome != "" {
+			if _, err := parseOutcome(step.Execute.Outcome); err != nil {
+				return fmt.Errorf("invalid expectation in step %d: %w", stepIndex+1, err)
+			}
+		}
+	}
+
+	if err := s.GameConfig().Validate(); err != nil {
+		return fmt.Errorf("invalid game configuration: %w", err)
+	}
+
+	return nil
+}
+
+// UnmarshalYAML reads a step, making sure that it does exactly one thing.
+// An execute step without any expectations can be written as just "execute".
+func (s *Step) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode && node.Value == "execute" {
+		s.Execute = &Expectation{}
+		return nil
+	}
+
+	if node.Kind != yaml.MappingNode || len(node.Content) != 2 {
+		return fmt.Errorf("line %d: a step must have exactly one of day, night, or execute", node.Line)
+	}
+
+	keyNode, valueNode := node.Content[0], node.Content[1]
+	switch keyNode.Value {
+	case "day":
+		return valueNode.Decode(&s.Day)
+	case "night":
+		return valueNode.Decode(&s.Night)
+	case "execute":
+		if valueNode.Kind == yaml.MappingNode {
+			for keyIndex := 0; keyIndex < len(valueNode.Content); keyIndex += 2 {
+				switch expectationNode := valueNode.Content[keyIndex]; expectationNode.Value {
+				case "convicted", "killed", "outcome":
+				default:
+					return fmt.Errorf("line %d: unknown expectation '%s'; expected convicted, killed, or outcome", expectationNode.Line, expectationNode.Value)
+				}
+			}
+		}
+
+		s.Execute = &Expectation{}
+		return valueNode.Decode(s.Execute)
+	default:
+		return fmt.Errorf("line %d: unknown step '%s'; expected day, night, or execute", keyNode.Line, keyNode.Value)
+	}
+}
+
+func parseOutcome(outcome string) (game.PhaseOutcome, error) {
+	switch outcome {
+	case "continuation":
+		return game.PhaseOutcomeContinuation, nil
+	case "civilian":
+		return game.PhaseOutcomeCivilianVictory, nil
+	case "mafia":
+		return game.PhaseOutcomeMafiaVictory, nil
+	default:
+		return 0, fmt.Errorf("unknown outcome '%s'; expected continuation, civilian, or mafia", outcome)
+	}
+}
+
+func parseRole(role string) (game.PlayerRole, error) {
+	switch role {
+	case "civilian":
+		return game.PlayerRoleCivilian, nil
+	case "mafia":
+		return game.PlayerRoleMafia, nil
+	default:
+		return 0, fmt.Errorf("unknown role '%s'; expected civilian or mafia", role)
+	}
+}
@@ -0,0 +1,82 @@
+package scenario_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jrh3k5/mafia-dapp-http/game"
+	"github.com/jrh3k5/mafia-dapp-http/scenario"
+)
+
+var _ = Describe("Parse", func() {
+	It("parses a scenario", func() {
+		parsedScenario, err := scenario.Parse([]byte(`
+name: the Mafia is caught
+players:
+  - address: player1
+    role: mafia
+  - address: player2
+    nickname: Second
+  - address: player3
+  - address: player4
+steps:
+  - day: {player2: player1, player3: player1}
+  - execute: {convicted: [player1], outcome: civilian}
+  - night: {player1: player2}
+  - execute
+`))
+		Expect(err).ToNot(HaveOccurred(), "parsing the scenario should not fail")
+		Expect(parsedScenario.HostAddress()).To(Equal("player1"), "the first player should host the game when no host is given")
+		Expect(parsedScenario.Steps).To(HaveLen(4), "every step should be parsed")
+		Expect(parsedScenario.Steps[0].Day).To(HaveKeyWithValue("player2", "player1"), "the accusations of the day should be parsed")
+		Expect(*parsedScenario.Steps[1].Execute.Convicted).To(ConsistOf("player1"), "the expected convictions should be parsed")
+		Expect(parsedScenario.Steps[1].Execute.Killed).To(BeNil(), "expectations that are not given should not be checked")
+		Expect(parsedScenario.Steps[2].Night).To(HaveKeyWithValue("player1", "player2"), "the votes of the night should be parsed")
+		Expect(parsedScenario.Steps[3].Execute).ToNot(BeNil(), "a bare execute step should execute the phase")
+
+		gameConfig := parsedScenario.GameConfig()
+		Expect(gameConfig.MafiaRatio).To(Equal(game.DefaultMafiaRatio), "settings that are not given should use their defaults")
+		Expect(gameConfig.ForcedRoles).To(Equal(map[string]game.PlayerRole{"player1": game.PlayerRoleMafia}), "only the roles that are given should be forced")
+	})
+
+	DescribeTable("rejects scenarios that cannot be played",
+		func(document string, expectedMessage string) {
+			_, err := scenario.Parse([]byte(document))
+			Expect(err).To(MatchError(ContainSubstring(expectedMessage)), "the scenario should be rejected")
+		},
+		Entry("unknown step", `
+players: [{address: player1}, {address: player2}, {address: player3}]
+steps:
+  - dusk: {player1: player2}
+`, "unknown step 'dusk'"),
+		Entry("step that does more than one thing", `
+players: [{address: player1}, {address: player2}, {address: player3}]
+steps:
+  - day: {player1: player2}
+    execute: {outcome: continuation}
+`, "a step must have exactly one of day, night, or execute"),
+		Entry("unknown expectation", `
+players: [{address: player1}, {address: player2}, {address: player3}]
+steps:
+  - execute: {lynched: [player2]}
+`, "unknown expectation 'lynched'"),
+		Entry("unknown role", `
+players: [{address: player1, role: detective}, {address: player2}, {address: player3}]
+`, "unknown role 'detective'"),
+		Entry("unknown outcome", `
+players: [{address: player1}, {address: player2}, {address: player3}]
+steps:
+  - execute: {outcome: draw}
+`, "unknown outcome 'draw'"),
+		Entry("player listed more than once", `
+players: [{address: player1}, {address: player2}, {address: player1}]
+`, "player 'player1' is listed more than once"),
+		Entry("unknown field", `
+players: [{address: player1}, {address: player2}, {address: player3}]
+hosts: player1
+`, "field hosts not found"),
+		Entry("no players", `
+name: empty
+`, "a scenario must have players"),
+	)
+})
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/config"
+)
+
+// corsPolicy decides which cross-origin requests browsers are allowed to make
+type corsPolicy struct {
+	allowAllOrigins  bool
+	allowedOrigins   map[string]bool
+	allowedMethods   map[string]bool
+	allowAllHeaders  bool
+	allowedHeaders   string
+	allowCredentials bool
+	maxAgeSeconds    string
+}
+
+func newCORSPolicy(cfg *config.Config) *corsPolicy {
+	policy := &corsPolicy{
+		allowedOrigins:   make(map[string]bool, len(cfg.AllowedOrigins)),
+		allowedMethods:   make(map[string]bool, len(cfg.AllowedMethods)),
+		allowCredentials: cfg.AllowCredentials,
+		maxAgeSeconds:    strconv.Itoa(int(cfg.CORSMaxAge.Seconds())),
+	}
+
+	for _, allowedOrigin := range cfg.AllowedOrigins {
+		if allowedOrigin == "*" {
+			policy.allowAllOrigins = true
+		}
+		policy.allowedOrigins[allowedOrigin] = true
+	}
+
+	for _, allowedMethod := range cfg.AllowedMethods {
+		policy.allowedMethods[strings.ToUpper(allowedMethod)] = true
+	}
+
+	var allowedHeaders []string
+	for _, allowedHeader := range cfg.AllowedHeaders {
+		if allowedHeader == "*" {
+			policy.allowAllHeaders = true
+		}
+		allowedHeaders = append(allowedHeaders, allowedHeader)
+	}
+	policy.allowedHeaders = strings.Join(allowedHeaders, ", ")
+
+	return policy
+}
+
+// middleware builds a handler that tells browsers which origins may read the response
+func (p *corsPolicy) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p.allowOrigin(c)
+
+		c.Next()
+	}
+}
+
+// registerPreflightHandlers answers preflight requests for every route registered so far, advertising the methods that each route supports
+func (p *corsPolicy) registerPreflightHandlers(r *gin.Engine) {
+	routeMethods := make(map[string][]string)
+	for _, route := range r.Routes() {
+		if route.Method == http.MethodOptions || !p.allowedMethods[route.Method] {
+			continue
+		}
+
+		preflightPath := toPreflightPath(route.Path)
+		routeMethods[preflightPath] = append(routeMethods[preflightPath], route.Method)
+	}
+
+	for preflightPath, methods := range routeMethods {
+		sort.Strings(methods)
+		r.OPTIONS(preflightPath, p.newPreflightHandler(strings.Join(methods, ", ")))
+	}
+}
+
+func (p *corsPolicy) newPreflightHandler(allowedMethods string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// the origin has already been handled by the middleware; if it was not allowed, then the browser will reject the response
+		if c.Writer.Header().Get("Access-Control-Allow-Origin") == "" {
+			c.Status(http.StatusNoContent)
+			return
+		}
+
+		c.Header("Access-Control-Allow-Methods", allowedMethods)
+
+		if p.allowAllHeaders {
+			// a literal "*" does not cover credentialed requests, so echo whatever the browser asked for
+			if requestedHeaders := c.GetHeader("Access-Control-Request-Headers"); requestedHeaders != "" {
+				c.Header("Access-Control-Allow-Headers", requestedHeaders)
+				c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+			}
+		} else if p.allowedHeaders != "" {
+			c.Header("Access-Control-Allow-Headers", p.allowedHeaders)
+		}
+
+		c.Header("Access-Control-Max-Age", p.maxAgeSeconds)
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (p *corsPolicy) allowOrigin(c *gin.Context) {
+	origin := c.GetHeader("Origin")
+
+	// a wildcard cannot be used for credentialed requests, so the origin has to be echoed instead
+	if p.allowAllOrigins && !p.allowCredentials {
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Header("Access-Control-Expose-Headers", requestIDHeader)
+		return
+	}
+
+	if origin == "" || (!p.allowAllOrigins && !p.allowedOrigins[origin]) {
+		return
+	}
+
+	c.Header("Access-Control-Allow-Origin", origin)
+	c.Header("Access-Control-Expose-Headers", requestIDHeader)
+	c.Writer.Header().Add("Vary", "Origin")
+	if p.allowCredentials {
+		c.Header("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// toPreflightPath renames the parameters in the given route path by their position.
+// Routes for different methods can name the same parameter differently (e.g., ":playerAddress" and ":voterAddress"),
+// but gin requires the names to agree once all of those routes share the OPTIONS method.
+func toPreflightPath(routePath string) string {
+	segments := strings.Split(routePath, "/")
+	for segmentIndex, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[segmentIndex] = segment[:1] + "param" + strconv.Itoa(segmentIndex)
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+	"github.com/jrh3k5/mafia-dapp-http/logging"
+)
+
+// requestIDHeader carries the ID of a request, so that a failure seen by the UI can be found in the server's logs
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength is the longest request ID accepted from a caller; longer IDs are replaced
+const maxRequestIDLength = 128
+
+// requestLogger builds a handler that assigns an ID to each request and logs each request once it has been handled.
+// A request ID given by the caller is kept; otherwise, one is generated. Either way, it is returned in the response and included in every message logged while handling the request.
+func requestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestStart := time.Now()
+
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRequestID()
+		}
+		c.Header(requestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logging.WithAttrs(c.Request.Context(), slog.String("requestID", requestID)))
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		attrs := []any{
+			"method", c.Request.Method,
+			"route", route,
+			"status", c.Writer.Status(),
+			"duration", time.Since(requestStart),
+		}
+		if hostAddress := c.Param("hostAddress"); hostAddress != "" {
+			attrs = append(attrs, "hostAddress", hostAddress)
+		}
+		if playerAddress := getPlayerAddress(c); playerAddress != "" {
+			attrs = append(attrs, "playerAddress", playerAddress)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "error", strings.Join(c.Errors.Errors(), "; "))
+		}
+
+		level := slog.LevelInfo
+		if c.Writer.Status() >= 500 && !isShutdownRelease(c) {
+			level = slog.LevelError
+		}
+
+		logger.Log(c.Request.Context(), level, "handled request", attrs...)
+	}
+}
+
+// isShutdownRelease determines whether the request was rejected because the server is shutting down, which is expected and so not an error
+func isShutdownRelease(c *gin.Context) bool {
+	for _, requestErr := range c.Errors {
+		if errors.Is(requestErr.Err, game.ErrShuttingDown) {
+			return true
+		}
+	}
+	return false
+}
+
+// getPlayerAddress finds the address of the player on whose behalf a request was made, if any
+func getPlayerAddress(c *gin.Context) string {
+	for _, paramName := range []string{"voterAddress", "playerAddress", "spectatorAddress"} {
+		if playerAddress := c.Param(paramName); playerAddress != "" {
+			return playerAddress
+		}
+	}
+
+	// some routes, such as joining a game, name the player in the query instead
+	return c.Query("playerAddress")
+}
+
+func newRequestID() string {
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(idBytes); err != nil {
+		// the time is unique enough to tell requests apart
+		return strings.ReplaceAll(time.Now().Format("150405.000000000"), ".", "")
+	}
+	return hex.EncodeToString(idBytes)
+}
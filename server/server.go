@@ -1,39 +1,139 @@
 package server
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jrh3k5/mafia-dapp-http/bots"
+	"github.com/jrh3k5/mafia-dapp-http/config"
 	"github.com/jrh3k5/mafia-dapp-http/controllers"
+	"github.com/jrh3k5/mafia-dapp-http/faults"
 	"github.com/jrh3k5/mafia-dapp-http/game"
+	"github.com/jrh3k5/mafia-dapp-http/metrics"
+	"github.com/jrh3k5/mafia-dapp-http/openapi"
 )
 
-func NewServer() *gin.Engine {
-	var gameEngine game.Engine = game.NewInMemoryGameEngine()
+// Server handles requests for games; it can be shut down gracefully, releasing every long poll before the requests are drained
+type Server struct {
+	*gin.Engine
+	shutdownEngine game.ShutdownEngine
+	botManager     *bots.Manager
+	snapshotPath   string
+	logger         *slog.Logger
+}
+
+// NewServer builds the server according to the given configuration, logging to the given logger.
+// Requests are validated against the OpenAPI document served at /openapi.json.
+// In dev mode, the admin endpoints that force games into particular states and inject faults into requests are enabled, and every request to them must carry the configured admin token.
+func NewServer(cfg *config.Config, logger *slog.Logger) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	var inMemoryEngine *game.InMemoryEngine
+	switch cfg.EngineBackend {
+	case config.EngineBackendMemory:
+		inMemoryEngine = game.NewInMemoryGameEngine(logger)
+	default:
+		return nil, fmt.Errorf("unsupported engine backend: '%s'", cfg.EngineBackend)
+	}
+
+	apiDocument, err := openapi.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI document: %w", err)
+	}
+
+	serverMetrics := metrics.New(inMemoryEngine)
+	gameEngine := serverMetrics.InstrumentEngine(inMemoryEngine)
+	botManager := bots.NewManager(gameEngine, inMemoryEngine, logger)
+
+	r := gin.New()
+
+	// measure requests from before they are touched by any other middleware, so that injected latency is included
+	r.Use(serverMetrics.Middleware())
+	r.Use(requestLogger(logger))
+	r.Use(gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		_ = c.Error(fmt.Errorf("panic while handling request: %v", recovered))
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
 
-	r := gin.Default()
+	corsPolicy := newCORSPolicy(cfg)
+	r.Use(corsPolicy.middleware())
 
-	r.Use(func(c *gin.Context) {
-		// allow everything
-		c.Writer.Header().Add("Access-Control-Allow-Origin", "*")
+	r.Use(apiDocument.Middleware())
 
-		c.Next()
-	})
+	var faultInjector *faults.Injector
+	if cfg.DevMode {
+		// the admin endpoints are left alone so that the faults can always be turned off, as are the metrics so that they can still be scraped
+		faultInjector = faults.NewInjector(logger, "/admin/", "/metrics")
+		r.Use(faultInjector.Middleware())
+	}
 
-	r.POST("/game/:hostAddress", controllers.NewInitializeGameHandler(gameEngine))
+	r.GET("/openapi.json", controllers.NewOpenAPIHandler(apiDocument))
+	r.GET("/docs", controllers.NewDocsHandler())
+	r.GET("/metrics", controllers.NewMetricsHandler(serverMetrics))
+	r.GET("/archive/games", controllers.NewGetArchivedGamesHandler(gameEngine))
+	r.GET("/archive/games/:gameID", controllers.NewGetArchivedGameHandler(gameEngine))
+	r.GET("/games", controllers.NewGetLobbyHandler(gameEngine))
+	r.GET("/games/wait", controllers.NewLobbyWaitHandler(gameEngine, cfg.WaitTimeout))
+	r.POST("/game/:hostAddress", controllers.NewInitializeGameHandler(gameEngine, cfg.DevMode))
 	r.DELETE("/game/:hostAddress", controllers.NewCancelGameHandler(gameEngine))
-	r.OPTIONS("/game/:hostAddress", func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Methods", http.MethodDelete)
-		c.Status(http.StatusOK)
-	})
+	r.POST("/game/:hostAddress/bots", controllers.NewAddBotsHandler(botManager))
+	r.GET("/game/:hostAddress/chat/:channel", controllers.NewGetChatMessagesHandler(gameEngine))
+	r.POST("/game/:hostAddress/chat/:channel", controllers.NewPostChatMessageHandler(gameEngine))
+	r.GET("/game/:hostAddress/chat/:channel/wait", controllers.NewChatMessageWaitHandler(gameEngine, cfg.WaitTimeout))
+	r.GET("/game/:hostAddress/config", controllers.NewGetGameConfigHandler(gameEngine))
+	r.POST("/game/:hostAddress/finish", controllers.NewFinishGameHandler(gameEngine))
 	r.POST("/game/:hostAddress/join", controllers.NewJoinHandler(gameEngine))
 	r.POST("/game/:hostAddress/phase/execute", controllers.NewPhaseExecutionHandler(gameEngine))
-	r.GET("/game/:hostAddress/phase/wait", controllers.NewPhaseExecutionWaitHandler(gameEngine))
+	r.GET("/game/:hostAddress/phase/wait", controllers.NewPhaseExecutionWaitHandler(gameEngine, cfg.WaitTimeout))
 	r.GET("/game/:hostAddress/players", controllers.NewGetPlayersHandler(gameEngine))
 	r.GET("/game/:hostAddress/players/:playerAddress", controllers.NewGetPlayerHandler(gameEngine))
+	r.GET("/game/:hostAddress/players/:playerAddress/teammates", controllers.NewGetMafiaTeammatesHandler(gameEngine))
+	r.DELETE("/game/:hostAddress/players/:playerAddress", controllers.NewLeaveGameHandler(gameEngine))
 	r.POST("/game/:hostAddress/players/:voterAddress/vote/:action", controllers.NewPlayerVoteHandler(gameEngine))
+	r.POST("/game/:hostAddress/spectators", controllers.NewAddSpectatorHandler(gameEngine))
+	r.GET("/game/:hostAddress/spectators/:spectatorAddress", controllers.NewGetSpectatorHandler(gameEngine))
+	r.GET("/game/:hostAddress/spectators/:spectatorAddress/players", controllers.NewGetSpectatorPlayersHandler(gameEngine))
+	r.GET("/game/:hostAddress/spectators/:spectatorAddress/votes", controllers.NewGetSpectatorVotesHandler(gameEngine))
 	r.POST("/game/:hostAddress/start", controllers.NewStartGameHandler(gameEngine))
-	r.GET("/game/:hostAddress/start/wait", controllers.NewGameStartWaitHandler(gameEngine))
+	r.GET("/game/:hostAddress/start/wait", controllers.NewGameStartWaitHandler(gameEngine, cfg.WaitTimeout))
+	r.GET("/players/:playerAddress/stats", controllers.NewGetPlayerStatsHandler(gameEngine))
+
+	if cfg.DevMode {
+		var adminEngine game.AdminEngine = inMemoryEngine
+
+		admin := r.Group("/admin", controllers.NewAdminAuthMiddleware(cfg.AdminToken))
+		admin.GET("/faults", controllers.NewGetFaultsHandler(faultInjector))
+		admin.PUT("/faults", controllers.NewSetFaultsHandler(faultInjector))
+		admin.DELETE("/faults", controllers.NewClearFaultsHandler(faultInjector))
+		admin.POST("/game/:hostAddress/phase", controllers.NewAdminSetCurrentPhaseHandler(adminEngine))
+		admin.POST("/game/:hostAddress/phase/inject", controllers.NewAdminInjectPhaseExecutionHandler(adminEngine))
+		admin.POST("/game/:hostAddress/players/:playerAddress/role", controllers.NewAdminSetPlayerRoleHandler(adminEngine))
+		admin.POST("/game/:hostAddress/players/:playerAddress/status", controllers.NewAdminSetPlayerStatusHandler(adminEngine))
+		admin.GET("/game/:hostAddress/votes", controllers.NewAdminGetVotesHandler(gameEngine))
+		admin.DELETE("/game/:hostAddress/votes", controllers.NewAdminClearVotesHandler(adminEngine))
+	}
+
+	// this must come after all other routes are registered
+	corsPolicy.registerPreflightHandlers(r)
+
+	return &Server{
+		Engine:         r,
+		shutdownEngine: inMemoryEngine,
+		botManager:     botManager,
+		snapshotPath:   cfg.SnapshotPath,
+		logger:         logger,
+	}, nil
+}
+
+// Shutdown answers every long poll waiting on a game, and every one that comes after, with a 503 that tells the caller to retry, and stops every bot.
+// This should be called once the server has stopped accepting requests, so that the long polls do not hold up the draining of requests.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.botManager.Stop()
 
-	return r
+	return s.shutdownEngine.Shutdown(ctx)
 }
@@ -1,281 +1,1306 @@
 package server_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
-	"sync"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	gameclient "github.com/jrh3k5/mafia-dapp-http/client"
+	"github.com/jrh3k5/mafia-dapp-http/config"
+	"github.com/jrh3k5/mafia-dapp-http/game"
+	"github.com/jrh3k5/mafia-dapp-http/logging"
+	"github.com/jrh3k5/mafia-dapp-http/openapi"
+	"github.com/jrh3k5/mafia-dapp-http/scenario"
 	"github.com/jrh3k5/mafia-dapp-http/server"
 )
 
+// adminToken is the token that the admin endpoints require of every request in the suite
+const adminToken = "test-admin-token"
+
 var _ = Describe("Server", func() {
 	var ctx context.Context
 	var client resty.Client
 	var baseURL string
+	var logger *slog.Logger
 
 	BeforeEach(func() {
 		var cancelFn context.CancelFunc
 		ctx, cancelFn = context.WithTimeout(context.Background(), 10*time.Second)
 		DeferCleanup(cancelFn)
 
-		gameHandler := server.NewServer()
+		// only shown when a test fails, to explain what the server was doing
+		logger = logging.NewLogger(GinkgoWriter, config.LogFormatText, config.LogLevelDebug)
+
+		serverConfig := config.Default()
+		serverConfig.DevMode = true
+		serverConfig.AdminToken = adminToken
+		gameHandler, err := server.NewServer(serverConfig, logger)
+		Expect(err).ToNot(HaveOccurred(), "building the server should not fail")
 		httpServer := &http.Server{
-			Addr:    "0.0.0.0:3000",
 			Handler: gameHandler,
 		}
-		baseURL = "http://localhost:3000"
+		// listen before serving so that the server is accepting connections before the test starts making requests
+		listener, err := net.Listen("tcp", "localhost:0")
+		Expect(err).ToNot(HaveOccurred(), "opening a listener for the server should not fail")
+		baseURL = fmt.Sprintf("http://%s", listener.Addr().String())
 		go func() {
-			_ = httpServer.ListenAndServe()
+			_ = httpServer.Serve(listener)
 		}()
 		DeferCleanup(func() {
 			httpServer.Shutdown(ctx)
 		})
 
+		apiDocument, err := openapi.Load()
+		Expect(err).ToNot(HaveOccurred(), "loading the OpenAPI document should not fail")
 		client = *resty.New()
+		// the token is only required by the admin endpoints, and every other route ignores it
+		client.SetAuthToken(adminToken)
+		// every response in the suite must match the OpenAPI document, so that the UI is never surprised by a response
+		client.OnAfterResponse(func(_ *resty.Client, response *resty.Response) error {
+			request := response.RawResponse.Request
+			if request.Method == http.MethodOptions {
+				return nil
+			}
+
+			if err := apiDocument.ValidateResponse(request, response.StatusCode(), response.Header(), response.Body()); err != nil {
+				return fmt.Errorf("the response to %s %s does not match the OpenAPI document: %w", request.Method, request.URL.Path, err)
+			}
+			return nil
+		})
 	})
 
 	It("successfully plays an eight-person game", func() {
-		hostAddress := "gamehost"
-		executePhase := func() {
-			phaseExecutionURL := fmt.Sprintf("%s/game/%s/phase/execute", baseURL, hostAddress)
-			executeResponse, err := client.R().SetContext(ctx).Post(phaseExecutionURL)
-			Expect(err).ToNot(HaveOccurred(), "executing the phase should not fail")
-			Expect(executeResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code executing phase")
-			Expect(executeResponse.Header().Get("Access-Control-Allow-Origin")).To(Equal("*"), "the request should be allowed from all origins")
-		}
+		eightPlayerGame, err := scenario.Load("testdata/eight_player_game.yaml")
+		Expect(err).ToNot(HaveOccurred(), "loading the scenario should not fail")
 
-		initializeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s", baseURL, hostAddress))
-		Expect(err).ToNot(HaveOccurred(), "initializing the game should not fail")
-		Expect(initializeResponse.StatusCode()).To(Equal(http.StatusOK), "the game initialization response should signal success")
-		Expect(initializeResponse.Header().Get("Access-Control-Allow-Origin")).To(Equal("*"), "the request should be allowed from all origins")
+		mismatches, err := scenario.Run(ctx, gameclient.New(baseURL, nil), eightPlayerGame)
+		Expect(err).ToNot(HaveOccurred(), "playing the scenario should not fail")
+		Expect(mismatches).To(BeEmpty(), "the game should have played out as the scenario expected")
+	})
 
+	It("only reveals the Mafia to members of the Mafia", func() {
+		hostAddress := "teammatehost"
 		playerAddresses := []string{hostAddress,
 			"player0001", "player0002",
 			"player0003", "player0004",
-			"player0005", "player0006",
-			"player0007"}
+			"player0005", "player0006"}
 
-		joinWaitGroup := &sync.WaitGroup{}
-		joinWaitGroup.Add(len(playerAddresses))
+		civilianAddresses, mafiaPlayers := startGame(ctx, client, baseURL, hostAddress, playerAddresses)
 
-		startWaitGroup := &sync.WaitGroup{}
-		startWaitGroup.Add(len(playerAddresses))
+		Expect(mafiaPlayers).To(HaveLen(2), "there should have been two Mafia members assigned")
 
-		for _, playerAddress := range playerAddresses {
-			joiningAddress := playerAddress
-			go func() {
-				defer GinkgoRecover()
+		for mafiaIndex, mafiaAddress := range mafiaPlayers {
+			teammatesResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/players/%s/teammates", baseURL, hostAddress, mafiaAddress))
+			Expect(err).ToNot(HaveOccurred(), "getting teammates for '%s' should not fail", mafiaAddress)
+			Expect(teammatesResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code getting teammates for '%s'", mafiaAddress)
 
-				func() {
-					defer joinWaitGroup.Done()
+			var teammates []map[string]any
+			Expect(json.Unmarshal(teammatesResponse.Body(), &teammates)).ToNot(HaveOccurred(), "unmarshalling the teammates of '%s' should not fail", mafiaAddress)
+			Expect(teammates).To(HaveLen(1), "each Mafia member should see exactly one teammate")
+			Expect(teammates[0]["playerAddress"]).To(Equal(mafiaPlayers[1-mafiaIndex]), "the other Mafia member should be returned")
+			Expect(teammates[0]["playerNickname"]).To(Equal(mafiaPlayers[1-mafiaIndex]+"Nick"), "the other Mafia member's nickname should be returned")
+			Expect(teammates[0]).ToNot(HaveKey("playerRole"), "the role should not be returned")
+		}
 
-					fmt.Printf("Joining as '%s'\n", joiningAddress)
+		teammatesResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/players/%s/teammates", baseURL, hostAddress, civilianAddresses[0]))
+		Expect(err).ToNot(HaveOccurred(), "getting teammates for a civilian should not fail")
+		Expect(teammatesResponse.StatusCode()).To(Equal(http.StatusForbidden), "civilians should not be able to see the Mafia")
+	})
 
-					joinResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/join?playerAddress=%s&playerNickname=%s", baseURL, hostAddress, joiningAddress, fmt.Sprintf("%sNick", joiningAddress)))
-					Expect(err).ToNot(HaveOccurred(), "%s joining game should not fail", joiningAddress)
-					Expect(joinResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code when player '%s' joined game", joiningAddress)
-					Expect(joinResponse.Header().Get("Access-Control-Allow-Origin")).To(Equal("*"), "the request should be allowed from all origins")
-				}()
+	It("restricts chat channels by role and time of day", func() {
+		hostAddress := "chathost"
+		civilianAddresses, mafiaPlayers := startGame(ctx, client, baseURL, hostAddress, []string{hostAddress, "player0001", "player0002", "player0003", "player0004"})
+		Expect(mafiaPlayers).To(HaveLen(1), "there should be one Mafia member")
 
-				func() {
-					defer startWaitGroup.Done()
+		chatURL := func(channel string, playerAddress string) string {
+			return fmt.Sprintf("%s/game/%s/chat/%s?playerAddress=%s", baseURL, hostAddress, channel, playerAddress)
+		}
 
-					waitResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/start/wait", baseURL, hostAddress))
-					Expect(err).ToNot(HaveOccurred(), "waiting for game to start should not have failed")
-					Expect(waitResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code while waiting for game to start")
-					Expect(waitResponse.Header().Get("Access-Control-Allow-Origin")).To(Equal("*"), "the request should be allowed from all origins")
-				}()
-			}()
+		waitResponseChan := make(chan *resty.Response)
+		go func() {
+			defer GinkgoRecover()
+
+			waitResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/chat/public/wait?playerAddress=%s&after=0", baseURL, hostAddress, civilianAddresses[1]))
+			Expect(err).ToNot(HaveOccurred(), "waiting for chat messages should not fail")
+			waitResponseChan <- waitResponse
+		}()
+
+		postResponse, err := client.R().SetContext(ctx).SetBody(map[string]any{"message": "it was them"}).Post(chatURL("public", civilianAddresses[0]))
+		Expect(err).ToNot(HaveOccurred(), "posting to the public chat should not fail")
+		Expect(postResponse.StatusCode()).To(Equal(http.StatusOK), "civilians should be able to post to the public chat during the day; response body was '%s'", string(postResponse.Body()))
+
+		waitResponse := <-waitResponseChan
+		Expect(waitResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code waiting for chat messages")
+		var waitedMessages []map[string]any
+		Expect(json.Unmarshal(waitResponse.Body(), &waitedMessages)).ToNot(HaveOccurred(), "unmarshalling the waited chat messages should not fail")
+		Expect(waitedMessages).To(HaveLen(1), "the posted message should have been received by the waiter")
+		Expect(waitedMessages[0]["sequence"]).To(Equal(float64(1)), "the first message should have the first sequence number")
+		Expect(waitedMessages[0]["senderAddress"]).To(Equal(civilianAddresses[0]), "the sender should be identified")
+		Expect(waitedMessages[0]["message"]).To(Equal("it was them"), "the message should be returned")
+
+		mafiaDayResponse, err := client.R().SetContext(ctx).SetBody(map[string]any{"message": "too early"}).Post(chatURL("mafia", mafiaPlayers[0]))
+		Expect(err).ToNot(HaveOccurred(), "posting to the Mafia chat should not fail")
+		Expect(mafiaDayResponse.StatusCode()).ToNot(Equal(http.StatusOK), "the Mafia chat should not be usable during the day")
+
+		civilianMafiaReadResponse, err := client.R().SetContext(ctx).Get(chatURL("mafia", civilianAddresses[0]))
+		Expect(err).ToNot(HaveOccurred(), "reading the Mafia chat should not fail")
+		Expect(civilianMafiaReadResponse.StatusCode()).ToNot(Equal(http.StatusOK), "civilians should not be able to read the Mafia chat")
+
+		executeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/phase/execute", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "executing the phase should not fail")
+		Expect(executeResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code executing phase")
+
+		mafiaNightResponse, err := client.R().SetContext(ctx).SetBody(map[string]any{"message": "who next?"}).Post(chatURL("mafia", mafiaPlayers[0]))
+		Expect(err).ToNot(HaveOccurred(), "posting to the Mafia chat should not fail")
+		Expect(mafiaNightResponse.StatusCode()).To(Equal(http.StatusOK), "the Mafia should be able to chat at night; response body was '%s'", string(mafiaNightResponse.Body()))
+
+		publicNightResponse, err := client.R().SetContext(ctx).SetBody(map[string]any{"message": "hello?"}).Post(chatURL("public", civilianAddresses[0]))
+		Expect(err).ToNot(HaveOccurred(), "posting to the public chat should not fail")
+		Expect(publicNightResponse.StatusCode()).ToNot(Equal(http.StatusOK), "the public chat should not be usable at night")
+
+		mafiaReadResponse, err := client.R().SetContext(ctx).Get(chatURL("mafia", mafiaPlayers[0]) + "&after=0")
+		Expect(err).ToNot(HaveOccurred(), "reading the Mafia chat should not fail")
+		Expect(mafiaReadResponse.StatusCode()).To(Equal(http.StatusOK), "the Mafia should be able to read the Mafia chat")
+		var mafiaMessages []map[string]any
+		Expect(json.Unmarshal(mafiaReadResponse.Body(), &mafiaMessages)).ToNot(HaveOccurred(), "unmarshalling the Mafia chat messages should not fail")
+		Expect(mafiaMessages).To(HaveLen(1), "only the message posted at night should be in the Mafia chat")
+		Expect(mafiaMessages[0]["timeOfDay"]).To(Equal(float64(1)), "the message should have been posted at night")
+	})
+
+	It("archives games when they end", func() {
+		hostAddress := "archivehost"
+		civilianAddresses, mafiaPlayers := startGame(ctx, client, baseURL, hostAddress, []string{hostAddress, "player0001", "player0002", "player0003", "player0004"})
+
+		for _, accuserAddress := range append(civilianAddresses, mafiaPlayers...) {
+			accusedAddress := mafiaPlayers[0]
+			if accuserAddress == accusedAddress {
+				accusedAddress = civilianAddresses[0]
+			}
+			voteResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/players/%s/vote/accuse?playerAddress=%s", baseURL, hostAddress, accuserAddress, accusedAddress))
+			Expect(err).ToNot(HaveOccurred(), "accusing on behalf of '%s' should not fail", accuserAddress)
+			Expect(voteResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code accusing on behalf of '%s'", accuserAddress)
+		}
+
+		executeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/phase/execute", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "executing the phase should not fail")
+		Expect(executeResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code executing phase")
+		var executedPhase *phaseExecutionResponse
+		Expect(json.Unmarshal(executeResponse.Body(), &executedPhase)).ToNot(HaveOccurred(), "unmarshalling the executed phase should not fail")
+		Expect(executedPhase.ConvictedPlayers).To(Equal([]string{mafiaPlayers[0]}), "the accused Mafia member should have been convicted")
+		Expect(executedPhase.PhaseOutcome).To(Equal(1), "convicting the only Mafia member should be a civilian victory")
+
+		finishResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/finish", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "finishing the game should not fail")
+		Expect(finishResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code finishing game")
+
+		cancelledHostAddress := "cancelledhost"
+		initializeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s", baseURL, cancelledHostAddress))
+		Expect(err).ToNot(HaveOccurred(), "initializing the game should not fail")
+		Expect(initializeResponse.StatusCode()).To(Equal(http.StatusOK), "the game initialization response should signal success")
+
+		cancelResponse, err := client.R().SetContext(ctx).Delete(fmt.Sprintf("%s/game/%s", baseURL, cancelledHostAddress))
+		Expect(err).ToNot(HaveOccurred(), "cancelling the game should not fail")
+		Expect(cancelResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code cancelling game")
+
+		playersResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/players", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "getting the players of a finished game should not fail")
+		Expect(playersResponse.StatusCode()).ToNot(Equal(http.StatusOK), "a finished game should no longer be playable")
+
+		archiveResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/archive/games", baseURL))
+		Expect(err).ToNot(HaveOccurred(), "listing the archived games should not fail")
+		Expect(archiveResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code listing archived games")
+
+		var archivedGames []map[string]any
+		Expect(json.Unmarshal(archiveResponse.Body(), &archivedGames)).ToNot(HaveOccurred(), "unmarshalling the archived games should not fail")
+		Expect(archivedGames).To(HaveLen(2), "both ended games should be archived")
+		Expect(archivedGames[0]).To(And(HaveKeyWithValue("hostAddress", hostAddress), HaveKeyWithValue("cancelled", false), HaveKeyWithValue("phaseOutcome", float64(1)), HaveKeyWithValue("playerCount", float64(5)), HaveKey("startedAt")), "the finished game should be summarized")
+		Expect(archivedGames[1]).To(And(HaveKeyWithValue("hostAddress", cancelledHostAddress), HaveKeyWithValue("cancelled", true), Not(HaveKey("startedAt"))), "the cancelled game should be summarized")
+
+		archivedGameResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/archive/games/%s", baseURL, archivedGames[0]["id"]))
+		Expect(err).ToNot(HaveOccurred(), "getting the archived game should not fail")
+		Expect(archivedGameResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code getting archived game")
+
+		var archivedGame struct {
+			Players []struct {
+				PlayerAddress string `json:"playerAddress"`
+				PlayerRole    int    `json:"playerRole"`
+				Convicted     bool   `json:"convicted"`
+			} `json:"players"`
+			PhaseExecutions []*phaseExecutionResponse `json:"phaseExecutions"`
 		}
+		Expect(json.Unmarshal(archivedGameResponse.Body(), &archivedGame)).ToNot(HaveOccurred(), "unmarshalling the archived game should not fail")
+		Expect(archivedGame.Players).To(HaveLen(5), "all players should be archived")
+		for _, player := range archivedGame.Players {
+			if player.PlayerAddress == mafiaPlayers[0] {
+				Expect(player.PlayerRole).To(Equal(1), "the Mafia member's role should be revealed")
+				Expect(player.Convicted).To(BeTrue(), "the Mafia member should have been convicted")
+			} else {
+				Expect(player.PlayerRole).To(Equal(0), "the civilians' roles should be revealed")
+			}
+		}
+		Expect(archivedGame.PhaseExecutions).To(HaveLen(1), "the phase execution should be archived")
+		Expect(archivedGame.PhaseExecutions[0].ConvictedPlayers).To(Equal([]string{mafiaPlayers[0]}), "the conviction should be archived")
+
+		mafiaStatsResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/players/%s/stats", baseURL, mafiaPlayers[0]))
+		Expect(err).ToNot(HaveOccurred(), "getting the Mafia member's stats should not fail")
+		Expect(mafiaStatsResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code getting the Mafia member's stats")
+		var mafiaStats map[string]any
+		Expect(json.Unmarshal(mafiaStatsResponse.Body(), &mafiaStats)).ToNot(HaveOccurred(), "unmarshalling the Mafia member's stats should not fail")
+		Expect(mafiaStats).To(And(HaveKeyWithValue("gamesPlayed", float64(1)), HaveKeyWithValue("mafiaWins", float64(0)), HaveKeyWithValue("timesConvicted", float64(1)), HaveKeyWithValue("accusationAccuracy", float64(0))), "the Mafia member's stats should reflect the loss")
+
+		civilianStatsResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/players/%s/stats", baseURL, civilianAddresses[1]))
+		Expect(err).ToNot(HaveOccurred(), "getting a civilian's stats should not fail")
+		Expect(civilianStatsResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code getting a civilian's stats")
+		var civilianStats map[string]any
+		Expect(json.Unmarshal(civilianStatsResponse.Body(), &civilianStats)).ToNot(HaveOccurred(), "unmarshalling a civilian's stats should not fail")
+		Expect(civilianStats).To(And(HaveKeyWithValue("gamesPlayed", float64(1)), HaveKeyWithValue("civilianWins", float64(1)), HaveKeyWithValue("accusations", float64(1)), HaveKeyWithValue("accusationAccuracy", float64(1))), "the civilian's stats should reflect the win")
+
+		missingResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/archive/games/nope", baseURL))
+		Expect(err).ToNot(HaveOccurred(), "getting an unknown archived game should not fail")
+		Expect(missingResponse.StatusCode()).To(Equal(http.StatusNotFound), "an unknown archived game should not be found")
+	})
+
+	It("lists public games that can be joined", func() {
+		hostAddress := "lobbyhost"
+		getLobby := func(url string) *lobbyResponse {
+			getResponse, err := client.R().SetContext(ctx).Get(url)
+			Expect(err).ToNot(HaveOccurred(), "getting the lobby should not fail")
+			Expect(getResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code getting the lobby")
+
+			var lobby *lobbyResponse
+			Expect(json.Unmarshal(getResponse.Body(), &lobby)).ToNot(HaveOccurred(), "unmarshalling the lobby should not fail")
+			return lobby
+		}
+		joinGame := func(playerAddress string) *resty.Response {
+			joinResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/join?playerAddress=%s&playerNickname=%sNick", baseURL, hostAddress, playerAddress, playerAddress))
+			Expect(err).ToNot(HaveOccurred(), "%s joining game should not fail", playerAddress)
+			return joinResponse
+		}
+
+		initializeResponse, err := client.R().SetContext(ctx).SetBody(map[string]any{"maxPlayers": 3}).Post(fmt.Sprintf("%s/game/%s", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "initializing the game should not fail")
+		Expect(initializeResponse.StatusCode()).To(Equal(http.StatusOK), "the game initialization response should signal success")
 
-		joinWaitGroup.Wait()
+		privateResponse, err := client.R().SetContext(ctx).SetBody(map[string]any{"private": true}).Post(fmt.Sprintf("%s/game/privatehost", baseURL))
+		Expect(err).ToNot(HaveOccurred(), "initializing the private game should not fail")
+		Expect(privateResponse.StatusCode()).To(Equal(http.StatusOK), "the private game initialization response should signal success")
 
-		fmt.Println("All players joined; starting game")
+		Expect(joinGame(hostAddress).StatusCode()).To(Equal(http.StatusOK), "the host should be able to join")
+
+		lobby := getLobby(fmt.Sprintf("%s/games", baseURL))
+		Expect(lobby.Games).To(HaveLen(1), "only the public game should be listed")
+		Expect(lobby.Games[0]).To(Equal(&lobbyGameResponse{HostAddress: hostAddress, HostNickname: hostAddress + "Nick", PlayerCount: 1, MinPlayers: 3, Capacity: 3}), "the public game should be described")
+
+		waitedLobbyChan := make(chan *lobbyResponse)
+		go func() {
+			defer GinkgoRecover()
+
+			waitedLobbyChan <- getLobby(fmt.Sprintf("%s/games/wait?version=%d", baseURL, lobby.Version))
+		}()
+
+		// give the waiter time to start waiting
+		time.Sleep(250 * time.Millisecond)
+
+		Expect(joinGame("player0001").StatusCode()).To(Equal(http.StatusOK), "a player should be able to join")
+
+		waitedLobby := <-waitedLobbyChan
+		Expect(waitedLobby.Version).To(BeNumerically(">", lobby.Version), "the lobby version should have increased")
+		Expect(waitedLobby.Games).To(HaveLen(1), "the public game should still be listed")
+		Expect(waitedLobby.Games[0].PlayerCount).To(Equal(2), "the joined player should be counted")
+
+		earlyStartResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/start", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "starting the game early should not fail")
+		Expect(earlyStartResponse.StatusCode()).To(Equal(http.StatusConflict), "the game should not start without enough players")
+		var earlyStartError map[string]any
+		Expect(json.Unmarshal(earlyStartResponse.Body(), &earlyStartError)).ToNot(HaveOccurred(), "unmarshalling the early start error should not fail")
+		Expect(earlyStartError).To(HaveKeyWithValue("playersNeeded", float64(1)), "the host should be told how many more players are needed")
+
+		Expect(joinGame("player0002").StatusCode()).To(Equal(http.StatusOK), "a player should be able to join until the game is full")
+		Expect(joinGame("player0003").StatusCode()).To(Equal(http.StatusConflict), "a player should not be able to join a full game")
 
 		startResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/start", baseURL, hostAddress))
 		Expect(err).ToNot(HaveOccurred(), "starting the game should not fail")
 		Expect(startResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected response to starting game")
-		Expect(startResponse.Header().Get("Access-Control-Allow-Origin")).To(Equal("*"), "the request should be allowed from all origins")
 
-		startWaitGroup.Wait()
+		Expect(getLobby(fmt.Sprintf("%s/games", baseURL)).Games).To(BeEmpty(), "started games should not be listed")
+	})
 
-		fmt.Println("Waiting for all players to be notified of game start")
+	It("configures games with a configuration document", func() {
+		hostAddress := "confighost"
 
-		var civilianAddresses []string
-		var mafiaPlayers []string
-		for _, playerAddress := range playerAddresses {
-			playerInfoResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/players/%s", baseURL, hostAddress, playerAddress))
-			Expect(err).ToNot(HaveOccurred(), "getting info for player '%s' should not have failed", playerAddress)
-			Expect(playerInfoResponse.Header().Get("Access-Control-Allow-Origin")).To(Equal("*"), "the request should be allowed from all origins")
-
-			var infoResponse map[string]any
-			Expect(json.Unmarshal(playerInfoResponse.Body(), &infoResponse)).ToNot(HaveOccurred(), "unmarshalling the player '%s' info response from JSON should not fail", playerAddress)
-			Expect(infoResponse).To(And(HaveLen(3), HaveKey("playerAddress"), HaveKey("playerNickname"), HaveKey("playerRole")), "the expected player for player '%s' information must be present", playerAddress)
-			Expect(infoResponse["playerNickname"]).ToNot(BeEmpty(), "the user's nickname should be returned")
-			playerRole := infoResponse["playerRole"]
-			Expect(playerRole).To(Or(Equal(float64(0)), Equal(float64(1))), "the player role must be of an expected value")
-			switch infoResponse["playerRole"] {
-			case float64(0):
-				civilianAddresses = append(civilianAddresses, playerAddress)
-			case float64(1):
-				mafiaPlayers = append(mafiaPlayers, playerAddress)
-			}
+		unknownFieldResponse, err := client.R().SetContext(ctx).SetBody(map[string]any{"maxPlayer": 3}).Post(fmt.Sprintf("%s/game/%s", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "initializing the game with a typo should not fail")
+		Expect(unknownFieldResponse.StatusCode()).To(Equal(http.StatusBadRequest), "unknown configuration fields should be rejected")
+
+		invalidResponse, err := client.R().SetContext(ctx).SetBody(map[string]any{"minPlayers": 2}).Post(fmt.Sprintf("%s/game/%s", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "initializing the game with an invalid configuration should not fail")
+		Expect(invalidResponse.StatusCode()).To(Equal(http.StatusBadRequest), "invalid configurations should be rejected")
+
+		forcedRolesConfig := map[string]any{"forcedRoles": map[string]int{"player0001": 1}}
+		productionServer, err := server.NewServer(config.Default(), logger)
+		Expect(err).ToNot(HaveOccurred(), "building the server without dev mode should not fail")
+		forcedRolesRecorder := httptest.NewRecorder()
+		forcedRolesBody, err := json.Marshal(forcedRolesConfig)
+		Expect(err).ToNot(HaveOccurred(), "marshalling the forced roles should not fail")
+		productionServer.ServeHTTP(forcedRolesRecorder, httptest.NewRequest(http.MethodPost, fmt.Sprintf("/game/%s", hostAddress), bytes.NewReader(forcedRolesBody)))
+		Expect(forcedRolesRecorder.Code).To(Equal(http.StatusBadRequest), "forced roles should be rejected outside of dev mode")
+
+		forcedRolesResponse, err := client.R().SetContext(ctx).SetBody(forcedRolesConfig).Post(fmt.Sprintf("%s/game/%s", baseURL, "forcedroleshost"))
+		Expect(err).ToNot(HaveOccurred(), "initializing the game with forced roles should not fail")
+		Expect(forcedRolesResponse.StatusCode()).To(Equal(http.StatusOK), "forced roles should be accepted in dev mode")
+
+		config := map[string]any{
+			"private":              true,
+			"minPlayers":           4,
+			"maxPlayers":           6,
+			"mafiaCount":           1,
+			"mafiaRatio":           5,
+			"tiePolicy":            "all",
+			"dayDurationSeconds":   0,
+			"nightDurationSeconds": 1,
+			"startingPhase":        1,
+			"revealRoleOnDeath":    true,
+			"allowGodView":         false,
 		}
+		initializeResponse, err := client.R().SetContext(ctx).SetBody(config).Post(fmt.Sprintf("%s/game/%s", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "initializing the game should not fail")
+		Expect(initializeResponse.StatusCode()).To(Equal(http.StatusOK), "the game initialization response should signal success; response body was '%s'", string(initializeResponse.Body()))
 
-		Expect(civilianAddresses).To(HaveLen(6), "there should be six civilians")
-		Expect(mafiaPlayers).To(HaveLen(2), "there should have been two Mafia members assigned")
+		configResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/config", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "getting the game configuration should not fail")
+		Expect(configResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code getting the game configuration")
+		var returnedConfig map[string]any
+		Expect(json.Unmarshal(configResponse.Body(), &returnedConfig)).ToNot(HaveOccurred(), "unmarshalling the game configuration should not fail")
+		Expect(returnedConfig).To(HaveLen(len(config)), "all configuration should be returned")
+		for key, value := range config {
+			Expect(fmt.Sprint(returnedConfig[key])).To(Equal(fmt.Sprint(value)), "the configured value of '%s' should be returned", key)
+		}
 
-		// Game will progress as:
-		// - round 0: civ[5] gets convicted of being Mafia
-		// - round 1: civ[4] is killed (oh, no, three civvies versus two Mafia - on the verge of failure!)
-		// - round 2: mafia[1] is convicted as being mafia
-		// - round 3: civ[3] is killed
-		// - round 4: mafia[0] is convicted of being mafia - civilian victory!
+		phaseExecutionChan := make(chan *phaseExecutionResponse)
+		go func() {
+			defer GinkgoRecover()
 
-		// round 0
-		round0OutcomeChannel := make(chan *phaseExecutionResponse)
-		accuseAsMafia(ctx, client, baseURL, hostAddress, civilianAddresses[0:5], civilianAddresses[5], round0OutcomeChannel)
-		accuseAsMafia(ctx, client, baseURL, hostAddress, mafiaPlayers, civilianAddresses[5], round0OutcomeChannel)
-		accuseAsMafia(ctx, client, baseURL, hostAddress, []string{civilianAddresses[5]}, mafiaPlayers[0], round0OutcomeChannel)
+			waitResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/phase/wait", baseURL, hostAddress))
+			Expect(err).ToNot(HaveOccurred(), "waiting for phase execution should not fail")
+			Expect(waitResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code waiting for phase execution")
 
-		// Wait for all of the reuqests to subscribe and wait to be waiting
-		time.Sleep(250 * time.Millisecond)
+			var phaseExecution *phaseExecutionResponse
+			Expect(json.Unmarshal(waitResponse.Body(), &phaseExecution)).ToNot(HaveOccurred(), "failed to unmarshal response for phase execution waiting")
+			phaseExecutionChan <- phaseExecution
+		}()
 
-		executePhase()
+		_, mafiaPlayers := joinAndStartGame(ctx, client, baseURL, hostAddress, []string{hostAddress, "player0001", "player0002", "player0003", "player0004", "player0005"})
+		Expect(mafiaPlayers).To(HaveLen(1), "the configured number of Mafia members should be assigned")
 
-		waitForOutcomes(ctx, round0OutcomeChannel, len(playerAddresses), 0, 0, nil, []string{civilianAddresses[5]})
+		// the night should be executed automatically without any action by the host
+		phaseExecution := <-phaseExecutionChan
+		Expect(phaseExecution.CurrentPhase).To(Equal(1), "the game should have started at night")
+		Expect(phaseExecution.PhaseOutcome).To(Equal(0), "the game should continue")
+	})
 
-		// round 1
-		fmt.Println("Executing round 1")
-		round1OutcomeChannel := make(chan *phaseExecutionResponse)
-		voteToKill(ctx, client, baseURL, hostAddress, mafiaPlayers, civilianAddresses[4], round1OutcomeChannel)
+	It("lets spectators watch games", func() {
+		hostAddress := "spectatedhost"
+		addSpectator := func(gameHostAddress string, spectatorAddress string, godView bool) *resty.Response {
+			spectatorResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/spectators?spectatorAddress=%s&godView=%t", baseURL, gameHostAddress, spectatorAddress, godView))
+			Expect(err).ToNot(HaveOccurred(), "adding spectator '%s' should not fail", spectatorAddress)
+			return spectatorResponse
+		}
+		getSpectatedPlayers := func(spectatorAddress string) []map[string]any {
+			playersResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/spectators/%s/players", baseURL, hostAddress, spectatorAddress))
+			Expect(err).ToNot(HaveOccurred(), "getting the players as spectator '%s' should not fail", spectatorAddress)
+			Expect(playersResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code getting players as spectator '%s'", spectatorAddress)
 
-		// Wait for all of the reuqests to subscribe and wait to be waiting
-		time.Sleep(250 * time.Millisecond)
+			var players []map[string]any
+			Expect(json.Unmarshal(playersResponse.Body(), &players)).ToNot(HaveOccurred(), "unmarshalling the players should not fail")
+			return players
+		}
 
-		executePhase()
+		initializeResponse, err := client.R().SetContext(ctx).SetBody(map[string]any{"allowGodView": true}).Post(fmt.Sprintf("%s/game/%s", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "initializing the game should not fail")
+		Expect(initializeResponse.StatusCode()).To(Equal(http.StatusOK), "the game initialization response should signal success")
 
-		waitForOutcomes(ctx, round1OutcomeChannel, len(mafiaPlayers), 0, 1, []string{civilianAddresses[4]}, nil)
+		Expect(addSpectator(hostAddress, "qa", true).StatusCode()).To(Equal(http.StatusOK), "a god view spectator should be allowed")
+		Expect(addSpectator(hostAddress, "viewer", false).StatusCode()).To(Equal(http.StatusOK), "a spectator should be allowed")
 
-		// round 2
-		fmt.Println("Executing round 2")
-		round2OutcomeChannel := make(chan *phaseExecutionResponse)
-		accuseAsMafia(ctx, client, baseURL, hostAddress, civilianAddresses[0:4], mafiaPlayers[1], round2OutcomeChannel)
-		accuseAsMafia(ctx, client, baseURL, hostAddress, mafiaPlayers, civilianAddresses[3], round2OutcomeChannel)
+		joinResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/join?playerAddress=viewer&playerNickname=viewer", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "joining as a spectator should not fail")
+		Expect(joinResponse.StatusCode()).ToNot(Equal(http.StatusOK), "spectators should not be able to join as players")
 
-		// Wait for all of the reuqests to subscribe and wait to be waiting
-		time.Sleep(250 * time.Millisecond)
+		civilianAddresses, mafiaPlayers := joinAndStartGame(ctx, client, baseURL, hostAddress, []string{hostAddress, "player0001", "player0002", "player0003", "player0004"})
 
-		executePhase()
+		godViewPlayers := getSpectatedPlayers("qa")
+		Expect(godViewPlayers).To(HaveLen(5), "spectators should not be counted as players")
+		for _, player := range godViewPlayers {
+			Expect(player).To(HaveKey("playerRole"), "the god view should include roles")
+		}
 
-		waitForOutcomes(ctx, round2OutcomeChannel, 4+2, 0, 0, nil, []string{mafiaPlayers[1]})
+		for _, player := range getSpectatedPlayers("viewer") {
+			Expect(player).ToNot(HaveKey("playerRole"), "spectators without the god view should not see roles")
+		}
 
-		// round 3
-		fmt.Println("Executing round 3")
-		round3OutcomeChannel := make(chan *phaseExecutionResponse)
-		voteToKill(ctx, client, baseURL, hostAddress, []string{mafiaPlayers[0]}, civilianAddresses[3], round3OutcomeChannel)
+		accuseResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/players/viewer/vote/accuse?playerAddress=%s", baseURL, hostAddress, mafiaPlayers[0]))
+		Expect(err).ToNot(HaveOccurred(), "accusing as a spectator should not fail")
+		Expect(accuseResponse.StatusCode()).ToNot(Equal(http.StatusOK), "spectators should not be able to vote")
 
-		// Wait for all of the reuqests to subscribe and wait to be waiting
-		time.Sleep(250 * time.Millisecond)
+		executeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/phase/execute", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "executing the phase should not fail")
+		Expect(executeResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code executing phase")
 
-		executePhase()
+		killResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/players/%s/vote/kill?playerAddress=%s", baseURL, hostAddress, mafiaPlayers[0], civilianAddresses[0]))
+		Expect(err).ToNot(HaveOccurred(), "voting to kill should not fail")
+		Expect(killResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code voting to kill")
 
-		waitForOutcomes(ctx, round3OutcomeChannel, 1, 0, 1, []string{civilianAddresses[3]}, nil)
+		votesResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/spectators/qa/votes", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "getting the votes as a god view spectator should not fail")
+		Expect(votesResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code getting votes")
+		var votes map[string]map[string]string
+		Expect(json.Unmarshal(votesResponse.Body(), &votes)).ToNot(HaveOccurred(), "unmarshalling the votes should not fail")
+		Expect(votes["killVotes"]).To(Equal(map[string]string{mafiaPlayers[0]: civilianAddresses[0]}), "the god view should see the night votes")
 
-		// round 4
-		fmt.Println("Executing round 4")
-		round4OutcomeChannel := make(chan *phaseExecutionResponse)
-		accuseAsMafia(ctx, client, baseURL, hostAddress, civilianAddresses[0:2], mafiaPlayers[0], round4OutcomeChannel)
-		accuseAsMafia(ctx, client, baseURL, hostAddress, []string{mafiaPlayers[0]}, civilianAddresses[0], round4OutcomeChannel)
+		forbiddenVotesResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/spectators/viewer/votes", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "getting the votes as a spectator should not fail")
+		Expect(forbiddenVotesResponse.StatusCode()).To(Equal(http.StatusForbidden), "spectators without the god view should not see votes")
 
-		// Wait for all of the reuqests to subscribe and wait to be waiting
+		closedHostAddress := "closedhost"
+		closedInitializeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s", baseURL, closedHostAddress))
+		Expect(err).ToNot(HaveOccurred(), "initializing the game should not fail")
+		Expect(closedInitializeResponse.StatusCode()).To(Equal(http.StatusOK), "the game initialization response should signal success")
+		Expect(addSpectator(closedHostAddress, "qa", true).StatusCode()).ToNot(Equal(http.StatusOK), "the god view should require the host's permission")
+	})
+
+	It("plays a game with bots", func() {
+		hostAddress := "bothost"
+
+		initializeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "initializing the game should not fail")
+		Expect(initializeResponse.StatusCode()).To(Equal(http.StatusOK), "the game initialization response should signal success")
+
+		// the host only runs the game so that every Mafia member is a bot
+		botsResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/bots?count=8&strategy=mafia&minDelayMs=0&maxDelayMs=50", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "adding bots should not fail")
+		Expect(botsResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code adding bots")
+		var addedBots map[string][]string
+		Expect(json.Unmarshal(botsResponse.Body(), &addedBots)).ToNot(HaveOccurred(), "unmarshalling the added bots should not fail")
+		Expect(addedBots["botAddresses"]).To(HaveLen(8), "all of the bots should have been added")
+
+		// give the bots time to start waiting for the game to start
 		time.Sleep(250 * time.Millisecond)
 
-		executePhase()
+		startResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/start", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "starting the game should not fail")
+		Expect(startResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected response to starting game")
+
+		// the Mafia bots kill a civilian every night, so the game must end within a bounded number of phases
+		for phaseIndex := 0; phaseIndex < 20; phaseIndex++ {
+			phaseExecutionChan := make(chan *phaseExecutionResponse)
+			go func() {
+				defer GinkgoRecover()
+
+				waitResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/phase/wait", baseURL, hostAddress))
+				Expect(err).ToNot(HaveOccurred(), "waiting for phase execution should not fail")
+				Expect(waitResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code waiting for phase execution")
+
+				var phaseExecution *phaseExecutionResponse
+				Expect(json.Unmarshal(waitResponse.Body(), &phaseExecution)).ToNot(HaveOccurred(), "failed to unmarshal response for phase execution waiting")
+				phaseExecutionChan <- phaseExecution
+			}()
+
+			// give the bots time to vote
+			time.Sleep(250 * time.Millisecond)
+
+			executeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/phase/execute", baseURL, hostAddress))
+			Expect(err).ToNot(HaveOccurred(), "executing the phase should not fail")
+			Expect(executeResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code executing phase")
 
-		waitForOutcomes(ctx, round4OutcomeChannel, 2+1, 1, 0, nil, []string{mafiaPlayers[0]})
+			phaseExecution := <-phaseExecutionChan
+			if phaseExecution.CurrentPhase == 1 {
+				Expect(phaseExecution.KilledPlayers).To(HaveLen(1), "the Mafia bots should agree on whom to kill")
+			}
+
+			if phaseExecution.PhaseOutcome != 0 {
+				return
+			}
+		}
+
+		Fail("the game should have ended")
 	})
-})
 
-func accuseAsMafia(ctx context.Context, client resty.Client, baseURL string, hostAddress string, accuserAddresses []string, accusedAddress string, phaseExecutionChan chan<- *phaseExecutionResponse) {
-	for _, accuserAddress := range accuserAddresses {
-		voteURL := fmt.Sprintf("%s/game/%s/players/%s/vote/accuse?playerAddress=%s", baseURL, hostAddress, accuserAddress, accusedAddress)
-		voteResponse, err := client.R().SetContext(ctx).Post(voteURL)
-		Expect(err).ToNot(HaveOccurred(), "failed to accuse '%s' of being mafia on behalf of user '%s'", accusedAddress, accuserAddress)
-		Expect(voteResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected response status code when accusing '%s' of being mafia on behalf of user '%s'; response body was '%s'", accusedAddress, accuserAddress, string(voteResponse.Body()))
-		Expect(voteResponse.Header().Get("Access-Control-Allow-Origin")).To(Equal("*"), "the request should be allowed from all origins")
+	It("limits the bots added to a game", func() {
+		hostAddress := "fullbothost"
+
+		initializeResponse, err := client.R().SetContext(ctx).SetBody(map[string]any{"maxPlayers": 3}).Post(fmt.Sprintf("%s/game/%s", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "initializing the game should not fail")
+		Expect(initializeResponse.StatusCode()).To(Equal(http.StatusOK), "the game initialization response should signal success")
+
+		tooManyResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/bots?count=51", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "adding too many bots should not fail")
+		Expect(tooManyResponse.StatusCode()).To(Equal(http.StatusBadRequest), "adding more bots than can be added at once should be rejected")
+
+		overflowResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/bots?count=5", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "overfilling the game with bots should not fail")
+		Expect(overflowResponse.StatusCode()).To(Equal(http.StatusConflict), "bots should not be able to join a full game")
+		var overflowError map[string]any
+		Expect(json.Unmarshal(overflowResponse.Body(), &overflowError)).ToNot(HaveOccurred(), "unmarshalling the error should not fail")
+		Expect(overflowError["botAddresses"]).To(HaveLen(3), "the error should list the bots that joined before the game was full")
+
+		players, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/players", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "getting the players should not fail")
+		var joinedPlayers []map[string]any
+		Expect(json.Unmarshal(players.Body(), &joinedPlayers)).ToNot(HaveOccurred(), "unmarshalling the players should not fail")
+		Expect(joinedPlayers).To(HaveLen(3), "the bots that joined before the game was full should stay in it")
+	})
+
+	It("lets players leave games", func() {
+		hostAddress := "leavehost"
+		leaveGame := func(gameHostAddress string, playerAddress string, requesterAddress string) *resty.Response {
+			leaveResponse, err := client.R().SetContext(ctx).Delete(fmt.Sprintf("%s/game/%s/players/%s?requesterAddress=%s", baseURL, gameHostAddress, playerAddress, requesterAddress))
+			Expect(err).ToNot(HaveOccurred(), "removing '%s' from the game should not fail", playerAddress)
+			return leaveResponse
+		}
+		getPlayerCount := func() int {
+			playersResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/players", baseURL, hostAddress))
+			Expect(err).ToNot(HaveOccurred(), "getting the players should not fail")
+
+			var players []map[string]any
+			Expect(json.Unmarshal(playersResponse.Body(), &players)).ToNot(HaveOccurred(), "unmarshalling the players should not fail")
+			return len(players)
+		}
+
+		initializeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "initializing the game should not fail")
+		Expect(initializeResponse.StatusCode()).To(Equal(http.StatusOK), "the game initialization response should signal success")
+
+		for _, playerAddress := range []string{hostAddress, "player0001", "player0002"} {
+			joinResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/join?playerAddress=%s&playerNickname=%sNick", baseURL, hostAddress, playerAddress, playerAddress))
+			Expect(err).ToNot(HaveOccurred(), "%s joining game should not fail", playerAddress)
+			Expect(joinResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code when player '%s' joined game", playerAddress)
+		}
+
+		Expect(leaveGame(hostAddress, "player0001", "player0002").StatusCode()).ToNot(Equal(http.StatusOK), "players should not be able to remove other players")
+		Expect(leaveGame(hostAddress, "player0001", "player0001").StatusCode()).To(Equal(http.StatusOK), "players should be able to leave")
+		Expect(leaveGame(hostAddress, "player0002", hostAddress).StatusCode()).To(Equal(http.StatusOK), "the host should be able to remove players")
+		Expect(getPlayerCount()).To(Equal(1), "only the host should remain in the game")
+
+		forfeitHostAddress := "forfeithost"
+		civilianAddresses, mafiaPlayers := startGame(ctx, client, baseURL, forfeitHostAddress, []string{forfeitHostAddress, "player0001", "player0002", "player0003", "player0004"})
 
-		sourceAddress := accuserAddress
+		Expect(leaveGame(forfeitHostAddress, civilianAddresses[0], civilianAddresses[0]).StatusCode()).To(Equal(http.StatusOK), "a civilian should be able to forfeit")
+		Expect(leaveGame(forfeitHostAddress, civilianAddresses[0], civilianAddresses[0]).StatusCode()).ToNot(Equal(http.StatusOK), "a player should not be able to forfeit twice")
 
-		// Now wait for the phase to finish executing
+		for _, civilianAddress := range civilianAddresses[1:] {
+			if civilianAddress != forfeitHostAddress {
+				Expect(leaveGame(forfeitHostAddress, civilianAddress, forfeitHostAddress).StatusCode()).To(Equal(http.StatusForbidden), "the host should not be able to remove players from a game in progress")
+				break
+			}
+		}
+
+		phaseExecutionChan := make(chan *phaseExecutionResponse)
 		go func() {
 			defer GinkgoRecover()
 
-			waitURL := fmt.Sprintf("%s/game/%s/phase/wait", baseURL, hostAddress)
-			waitResponse, err := client.R().SetContext(ctx).Get(waitURL)
-			Expect(err).ToNot(HaveOccurred(), "waiting for phase execution on behalf of '%s' failed", sourceAddress)
-			Expect(waitResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code waiting for phase execution on behalf of '%s'; response body is: %s", sourceAddress, string(waitResponse.Body()))
-			Expect(waitResponse.Header().Get("Access-Control-Allow-Origin")).To(Equal("*"), "the request should be allowed from all origins")
+			waitResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/phase/wait", baseURL, forfeitHostAddress))
+			Expect(err).ToNot(HaveOccurred(), "waiting for phase execution should not fail")
+			Expect(waitResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code waiting for phase execution")
 
 			var phaseExecution *phaseExecutionResponse
 			Expect(json.Unmarshal(waitResponse.Body(), &phaseExecution)).ToNot(HaveOccurred(), "failed to unmarshal response for phase execution waiting")
 			phaseExecutionChan <- phaseExecution
 		}()
-	}
-}
 
-func voteToKill(ctx context.Context, client resty.Client, baseURL string, hostAddress string, voterAddresses []string, victimAddress string, phaseExecutionChan chan<- *phaseExecutionResponse) {
-	for _, voterAddress := range voterAddresses {
-		voteURL := fmt.Sprintf("%s/game/%s/players/%s/vote/kill?playerAddress=%s", baseURL, hostAddress, voterAddress, victimAddress)
-		voteResponse, err := client.R().SetContext(ctx).Post(voteURL)
-		Expect(err).ToNot(HaveOccurred(), "failed to vote to kill '%s' on behalf of user '%s'", voterAddress, victimAddress)
-		Expect(voteResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected response status code when voting to kill '%s' on behalf of user '%s'; response body is '%s'", voterAddress, victimAddress, string(voteResponse.Body()))
-		Expect(voteResponse.Header().Get("Access-Control-Allow-Origin")).To(Equal("*"), "the request should be allowed from all origins")
+		// give the waiter time to start waiting
+		time.Sleep(250 * time.Millisecond)
+
+		Expect(leaveGame(forfeitHostAddress, mafiaPlayers[0], mafiaPlayers[0]).StatusCode()).To(Equal(http.StatusOK), "the Mafia member should be able to forfeit")
+
+		phaseExecution := <-phaseExecutionChan
+		Expect(phaseExecution.PhaseOutcome).To(Equal(1), "the Mafia forfeiting should be a civilian victory")
+		Expect(phaseExecution.ForfeitedPlayers).To(Equal([]string{mafiaPlayers[0]}), "the forfeiting player should be reported")
+
+		Expect(leaveGame(forfeitHostAddress, civilianAddresses[1], civilianAddresses[1]).StatusCode()).To(Equal(http.StatusConflict), "players should not be able to forfeit a game that has been won")
+
+		executeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/phase/execute", baseURL, forfeitHostAddress))
+		Expect(err).ToNot(HaveOccurred(), "requesting the phase execution should not fail")
+		Expect(executeResponse.StatusCode()).To(Equal(http.StatusConflict), "a game that has been won should not have any more phases executed")
+	})
+
+	It("forces game state through the admin endpoints", func() {
+		hostAddress := "adminhost"
+		civilianAddresses, mafiaPlayers := startGame(ctx, client, baseURL, hostAddress, []string{hostAddress, "player0001", "player0002", "player0003", "player0004"})
+		adminURL := func(path string) string {
+			return fmt.Sprintf("%s/admin/game/%s/%s", baseURL, hostAddress, path)
+		}
+		getPlayer := func(playerAddress string) map[string]any {
+			playersResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/players", baseURL, hostAddress))
+			Expect(err).ToNot(HaveOccurred(), "getting the players should not fail")
 
-		sourceAddress := voterAddress
+			var players []map[string]any
+			Expect(json.Unmarshal(playersResponse.Body(), &players)).ToNot(HaveOccurred(), "unmarshalling the players should not fail")
+			for _, player := range players {
+				if player["playerAddress"] == playerAddress {
+					return player
+				}
+			}
+			Fail(fmt.Sprintf("player '%s' was not listed", playerAddress))
+			return nil
+		}
 
-		// Now wait for the phase to finish executing
+		phaseExecutionChan := make(chan *phaseExecutionResponse)
 		go func() {
 			defer GinkgoRecover()
 
-			waitURL := fmt.Sprintf("%s/game/%s/phase/wait", baseURL, hostAddress)
-			waitResponse, err := client.R().SetContext(ctx).Get(waitURL)
-			Expect(err).ToNot(HaveOccurred(), "waiting for phase execution on behalf of '%s' failed", sourceAddress)
-			Expect(waitResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code waiting for phase execution on behalf of '%s'; response body is: '%s'", sourceAddress, string(waitResponse.Body()))
-			Expect(waitResponse.Header().Get("Access-Control-Allow-Origin")).To(Equal("*"), "the request should be allowed from all origins")
+			waitResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/phase/wait", baseURL, hostAddress))
+			Expect(err).ToNot(HaveOccurred(), "waiting for phase execution should not fail")
+			Expect(waitResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code waiting for phase execution")
 
 			var phaseExecution *phaseExecutionResponse
 			Expect(json.Unmarshal(waitResponse.Body(), &phaseExecution)).ToNot(HaveOccurred(), "failed to unmarshal response for phase execution waiting")
 			phaseExecutionChan <- phaseExecution
 		}()
-	}
-}
 
-func waitForOutcomes(ctx context.Context, outcomeChannel <-chan *phaseExecutionResponse, expectedCount int, expectedOutcome int, expectedPhase int, expectedKilledPlayers []string, expectedConvictedPlayers []string) {
-	var outcomeCount int
-	for {
-		if outcomeCount == expectedCount {
-			return
+		// give the waiter time to start waiting
+		time.Sleep(250 * time.Millisecond)
+
+		injectResponse, err := client.R().SetContext(ctx).SetBody(map[string]any{"phaseOutcome": 2, "currentPhase": 1, "killedPlayers": []string{civilianAddresses[0]}}).Post(adminURL("phase/inject"))
+		Expect(err).ToNot(HaveOccurred(), "injecting a phase execution should not fail")
+		Expect(injectResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code injecting a phase execution")
+
+		injectedExecution := <-phaseExecutionChan
+		Expect(injectedExecution.PhaseOutcome).To(Equal(2), "the injected outcome should be received")
+		Expect(injectedExecution.KilledPlayers).To(Equal([]string{civilianAddresses[0]}), "the injected victims should be received")
+		Expect(getPlayer(civilianAddresses[0])["dead"]).To(BeFalse(), "injecting a phase execution should not change the game")
+
+		roleResponse, err := client.R().SetContext(ctx).Post(adminURL(fmt.Sprintf("players/%s/role?playerRole=1", civilianAddresses[1])))
+		Expect(err).ToNot(HaveOccurred(), "assigning a role should not fail")
+		Expect(roleResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code assigning a role")
+		playerResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/players/%s", baseURL, hostAddress, civilianAddresses[1]))
+		Expect(err).ToNot(HaveOccurred(), "getting the player should not fail")
+		var player map[string]any
+		Expect(json.Unmarshal(playerResponse.Body(), &player)).ToNot(HaveOccurred(), "unmarshalling the player should not fail")
+		Expect(player["playerRole"]).To(Equal(float64(1)), "the player should have been moved into the Mafia")
+
+		statusResponse, err := client.R().SetContext(ctx).Post(adminURL(fmt.Sprintf("players/%s/status?dead=true", civilianAddresses[0])))
+		Expect(err).ToNot(HaveOccurred(), "marking a player dead should not fail")
+		Expect(statusResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code marking a player dead")
+		Expect(getPlayer(civilianAddresses[0])["dead"]).To(BeTrue(), "the player should have been marked dead")
+
+		accuseResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/players/%s/vote/accuse?playerAddress=%s", baseURL, hostAddress, civilianAddresses[2], mafiaPlayers[0]))
+		Expect(err).ToNot(HaveOccurred(), "accusing should not fail")
+		Expect(accuseResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code accusing")
+
+		clearResponse, err := client.R().SetContext(ctx).Delete(adminURL("votes"))
+		Expect(err).ToNot(HaveOccurred(), "clearing votes should not fail")
+		Expect(clearResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code clearing votes")
+
+		phaseResponse, err := client.R().SetContext(ctx).Post(adminURL("phase?timeOfDay=1"))
+		Expect(err).ToNot(HaveOccurred(), "setting the time of day should not fail")
+		Expect(phaseResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code setting the time of day")
+
+		executeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/phase/execute", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "executing the phase should not fail")
+		Expect(executeResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code executing phase")
+		var executedPhase *phaseExecutionResponse
+		Expect(json.Unmarshal(executeResponse.Body(), &executedPhase)).ToNot(HaveOccurred(), "unmarshalling the executed phase should not fail")
+		Expect(executedPhase.CurrentPhase).To(Equal(1), "the night should have been executed")
+		Expect(executedPhase.KilledPlayers).To(BeEmpty(), "no one should have been killed after the votes were cleared")
+
+		missingTokenResponse, err := resty.New().R().SetContext(ctx).Post(adminURL("phase?timeOfDay=0"))
+		Expect(err).ToNot(HaveOccurred(), "calling an admin endpoint without the token should not fail")
+		Expect(missingTokenResponse.StatusCode()).To(Equal(http.StatusUnauthorized), "a request without the admin token should be rejected")
+		wrongTokenResponse, err := client.R().SetContext(ctx).SetAuthToken("wrong-token").Post(adminURL("phase?timeOfDay=0"))
+		Expect(err).ToNot(HaveOccurred(), "calling an admin endpoint with the wrong token should not fail")
+		Expect(wrongTokenResponse.StatusCode()).To(Equal(http.StatusUnauthorized), "a request with the wrong admin token should be rejected")
+		Expect(getPlayer(civilianAddresses[0])["dead"]).To(BeTrue(), "rejected admin requests should not change the game")
+
+		productionServer, err := server.NewServer(config.Default(), logger)
+		Expect(err).ToNot(HaveOccurred(), "building the server without dev mode should not fail")
+		disabledRecorder := httptest.NewRecorder()
+		productionServer.ServeHTTP(disabledRecorder, httptest.NewRequest(http.MethodPost, fmt.Sprintf("/admin/game/%s/phase?timeOfDay=0", hostAddress), nil))
+		Expect(disabledRecorder.Code).To(Equal(http.StatusNotFound), "the admin endpoints should not exist outside of dev mode")
+	})
+
+	It("injects faults into requests", func() {
+		setFaults := func(config map[string]any) {
+			faultsResponse, err := client.R().SetContext(ctx).SetBody(config).Put(fmt.Sprintf("%s/admin/faults", baseURL))
+			Expect(err).ToNot(HaveOccurred(), "configuring faults should not fail")
+			Expect(faultsResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code configuring faults; response body was '%s'", string(faultsResponse.Body()))
+		}
+		lobbyURL := fmt.Sprintf("%s/games", baseURL)
+
+		invalidResponse, err := client.R().SetContext(ctx).SetBody(map[string]any{"global": map[string]any{"failureRate": 2}}).Put(fmt.Sprintf("%s/admin/faults", baseURL))
+		Expect(err).ToNot(HaveOccurred(), "configuring faults should not fail")
+		Expect(invalidResponse.StatusCode()).To(Equal(http.StatusBadRequest), "an invalid failure rate should be rejected")
+
+		setFaults(map[string]any{
+			"global": map[string]any{"failureRate": 1, "failureStatuses": []int{http.StatusBadGateway}},
+			"routes": map[string]any{
+				"GET /games/wait": map[string]any{"dropRate": 1},
+				"/archive/games":  map[string]any{"latency": map[string]any{"distribution": "fixed", "meanMs": 300}},
+			},
+		})
+
+		failedResponse, err := client.R().SetContext(ctx).Get(lobbyURL)
+		Expect(err).ToNot(HaveOccurred(), "getting the lobby should not fail")
+		Expect(failedResponse.StatusCode()).To(Equal(http.StatusBadGateway), "the global failure should have been injected")
+
+		_, err = client.R().SetContext(ctx).Get(fmt.Sprintf("%s/games/wait?version=0", baseURL))
+		Expect(err).To(HaveOccurred(), "the long poll should have been dropped")
+
+		requestStart := time.Now()
+		delayedResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/archive/games", baseURL))
+		Expect(err).ToNot(HaveOccurred(), "getting the archive should not fail")
+		Expect(delayedResponse.StatusCode()).To(Equal(http.StatusOK), "the route rule should replace the global rule")
+		Expect(time.Since(requestStart)).To(BeNumerically(">=", 300*time.Millisecond), "the request should have been delayed")
+
+		clearResponse, err := client.R().SetContext(ctx).Delete(fmt.Sprintf("%s/admin/faults", baseURL))
+		Expect(err).ToNot(HaveOccurred(), "clearing faults should not fail")
+		Expect(clearResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code clearing faults")
+
+		healthyResponse, err := client.R().SetContext(ctx).Get(lobbyURL)
+		Expect(err).ToNot(HaveOccurred(), "getting the lobby should not fail")
+		Expect(healthyResponse.StatusCode()).To(Equal(http.StatusOK), "no faults should be injected once they are cleared")
+	})
+
+	It("answers preflight requests for every route", func() {
+		preflight := func(path string, requestedMethod string) *resty.Response {
+			preflightResponse, err := client.R().SetContext(ctx).
+				SetHeader("Origin", "https://ui.example").
+				SetHeader("Access-Control-Request-Method", requestedMethod).
+				SetHeader("Access-Control-Request-Headers", "X-Auth-Token").
+				Options(baseURL + path)
+			Expect(err).ToNot(HaveOccurred(), "the preflight request to '%s' should not fail", path)
+			Expect(preflightResponse.StatusCode()).To(Equal(http.StatusNoContent), "unexpected status code for the preflight request to '%s'", path)
+			Expect(preflightResponse.Header().Get("Access-Control-Allow-Origin")).To(Equal("*"), "all origins should be allowed by default")
+			Expect(preflightResponse.Header().Get("Access-Control-Allow-Headers")).To(Equal("X-Auth-Token"), "the requested headers should be allowed by default")
+			Expect(preflightResponse.Header().Get("Access-Control-Max-Age")).To(Equal("600"), "preflight responses should be cacheable for ten minutes by default")
+			return preflightResponse
+		}
+
+		Expect(preflight("/game/preflighthost", http.MethodDelete).Header().Get("Access-Control-Allow-Methods")).To(Equal("DELETE, POST"), "the methods of the game route should be advertised")
+		Expect(preflight("/game/preflighthost/players/player0001/vote/accuse", http.MethodPost).Header().Get("Access-Control-Allow-Methods")).To(Equal("POST"), "the methods of the vote route should be advertised")
+		Expect(preflight("/game/preflighthost/players/player0001", http.MethodDelete).Header().Get("Access-Control-Allow-Methods")).To(Equal("DELETE, GET"), "the methods of the player route should be advertised")
+	})
+
+	It("only allows the configured origins", func() {
+		serverConfig := config.Default()
+		serverConfig.AllowedOrigins = []string{"https://allowed.example"}
+		serverConfig.AllowedHeaders = []string{"Content-Type", "Authorization"}
+		serverConfig.AllowCredentials = true
+		restrictedServer, err := server.NewServer(serverConfig, logger)
+		Expect(err).ToNot(HaveOccurred(), "building the server should not fail")
+
+		allowedRecorder := httptest.NewRecorder()
+		allowedRequest := httptest.NewRequest(http.MethodGet, "/games", nil)
+		allowedRequest.Header.Set("Origin", "https://allowed.example")
+		restrictedServer.ServeHTTP(allowedRecorder, allowedRequest)
+		Expect(allowedRecorder.Header().Get("Access-Control-Allow-Origin")).To(Equal("https://allowed.example"), "the configured origin should be allowed")
+		Expect(allowedRecorder.Header().Get("Access-Control-Allow-Credentials")).To(Equal("true"), "credentials should be allowed")
+
+		preflightRecorder := httptest.NewRecorder()
+		preflightRequest := httptest.NewRequest(http.MethodOptions, "/game/originhost/start", nil)
+		preflightRequest.Header.Set("Origin", "https://allowed.example")
+		preflightRequest.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		restrictedServer.ServeHTTP(preflightRecorder, preflightRequest)
+		Expect(preflightRecorder.Code).To(Equal(http.StatusNoContent), "unexpected status code for the preflight request")
+		Expect(preflightRecorder.Header().Get("Access-Control-Allow-Origin")).To(Equal("https://allowed.example"), "the configured origin should be allowed to make the request")
+		Expect(preflightRecorder.Header().Get("Access-Control-Allow-Headers")).To(Equal("Content-Type, Authorization"), "the configured headers should be allowed")
+
+		disallowedRecorder := httptest.NewRecorder()
+		disallowedRequest := httptest.NewRequest(http.MethodGet, "/games", nil)
+		disallowedRequest.Header.Set("Origin", "https://disallowed.example")
+		restrictedServer.ServeHTTP(disallowedRecorder, disallowedRequest)
+		Expect(disallowedRecorder.Header().Get("Access-Control-Allow-Origin")).To(BeEmpty(), "other origins should not be allowed")
+
+		invalidConfig := config.Default()
+		invalidConfig.EngineBackend = "postgres"
+		_, err = server.NewServer(invalidConfig, logger)
+		Expect(err).To(HaveOccurred(), "an unsupported engine backend should be rejected")
+	})
+
+	It("describes every route in the OpenAPI document", func() {
+		documentResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/openapi.json", baseURL))
+		Expect(err).ToNot(HaveOccurred(), "getting the OpenAPI document should not fail")
+		Expect(documentResponse.StatusCode()).To(Equal(http.StatusOK), "the OpenAPI document should be served")
+
+		var apiDocument struct {
+			Paths map[string]map[string]any `json:"paths"`
+		}
+		Expect(json.Unmarshal(documentResponse.Body(), &apiDocument)).ToNot(HaveOccurred(), "unmarshalling the OpenAPI document should not fail")
+
+		serverConfig := config.Default()
+		serverConfig.DevMode = true
+		serverConfig.AdminToken = adminToken
+		gameHandler, err := server.NewServer(serverConfig, logger)
+		Expect(err).ToNot(HaveOccurred(), "building the server should not fail")
+		for _, route := range gameHandler.Routes() {
+			if route.Method == http.MethodOptions {
+				continue
+			}
+
+			documentedPath := pathParamPattern.ReplaceAllString(route.Path, "{$1}")
+			Expect(apiDocument.Paths).To(HaveKey(documentedPath), "%s should be described", route.Path)
+			Expect(apiDocument.Paths[documentedPath]).To(HaveKey(strings.ToLower(route.Method)), "%s %s should be described", route.Method, route.Path)
 		}
 
-		select {
-		case <-ctx.Done():
-			Expect(ctx.Err()).ToNot(HaveOccurred(), "test should not have timed out (having seen %d outcomes)", outcomeCount)
-		case phaseOutcome := <-outcomeChannel:
-			outcomeCount++
-			Expect(phaseOutcome.PhaseOutcome).To(Equal(expectedOutcome), "unexpected phase outcome")
-			Expect(phaseOutcome.CurrentPhase).To(Equal(expectedPhase), "unexpected current phase")
-			Expect(phaseOutcome.ConvictedPlayers).To(Equal(expectedConvictedPlayers), "unexpected convicted players")
-			Expect(phaseOutcome.KilledPlayers).To(Equal(expectedKilledPlayers), "unexpected killed players")
+		docsResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/docs", baseURL))
+		Expect(err).ToNot(HaveOccurred(), "getting the docs page should not fail")
+		Expect(docsResponse.StatusCode()).To(Equal(http.StatusOK), "the docs page should be served")
+		Expect(docsResponse.String()).To(ContainSubstring("/openapi.json"), "the docs page should render the OpenAPI document")
+	})
+
+	It("rejects requests that do not match the OpenAPI document", func() {
+		hostAddress := "validationhost"
+		initializeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "initializing the game should not fail")
+		Expect(initializeResponse.StatusCode()).To(Equal(http.StatusOK), "the game should be initialized")
+
+		expectInvalidRequest := func(response *resty.Response, expectedMessage string, description string) {
+			Expect(response.StatusCode()).To(Equal(http.StatusBadRequest), "%s should be rejected", description)
+
+			var errorBody map[string]any
+			Expect(json.Unmarshal(response.Body(), &errorBody)).ToNot(HaveOccurred(), "unmarshalling the error for %s should not fail", description)
+			Expect(errorBody).To(HaveKeyWithValue("code", "invalid_request"), "unexpected error code for %s", description)
+			Expect(errorBody).To(HaveKeyWithValue("message", ContainSubstring(expectedMessage)), "the error for %s should say what is wrong", description)
+		}
+
+		missingParamResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/join?playerNickname=nick", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "joining without a player address should not fail")
+		expectInvalidRequest(missingParamResponse, "playerAddress", "joining without a player address")
+
+		wrongTypeResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/games/wait?version=latest", baseURL))
+		Expect(err).ToNot(HaveOccurred(), "waiting on a non-numeric lobby version should not fail")
+		expectInvalidRequest(wrongTypeResponse, "version", "waiting on a non-numeric lobby version")
+
+		unknownActionResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/players/player0001/vote/abstain?playerAddress=player0002", baseURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "casting an unknown kind of vote should not fail")
+		expectInvalidRequest(unknownActionResponse, "action", "casting an unknown kind of vote")
+
+		invalidBodyResponse, err := client.R().SetContext(ctx).SetBody(map[string]any{"tiePolicy": "coinflip"}).Post(fmt.Sprintf("%s/game/anotherhost", baseURL))
+		Expect(err).ToNot(HaveOccurred(), "initializing a game with an unknown tie policy should not fail")
+		expectInvalidRequest(invalidBodyResponse, "tiePolicy", "initializing a game with an unknown tie policy")
+	})
+
+	It("describes failures of the game engine with status codes and error codes", func() {
+		expectError := func(response *resty.Response, expectedStatus int, expectedCode string, description string) {
+			Expect(response.StatusCode()).To(Equal(expectedStatus), "unexpected status when %s", description)
+
+			var errorBody map[string]any
+			Expect(json.Unmarshal(response.Body(), &errorBody)).ToNot(HaveOccurred(), "unmarshalling the error when %s should not fail", description)
+			Expect(errorBody).To(HaveKeyWithValue("code", expectedCode), "unexpected error code when %s", description)
+			Expect(errorBody).To(HaveKeyWithValue("message", Not(BeEmpty())), "the error when %s should be described", description)
+		}
+
+		missingGameResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/missinghost/players/player0001/vote/accuse?playerAddress=player0002", baseURL))
+		Expect(err).ToNot(HaveOccurred(), "voting in a missing game should not fail")
+		expectError(missingGameResponse, http.StatusNotFound, "not_found", "voting in a missing game")
+
+		hostAddress := "errorshost"
+		civilianAddresses, mafiaPlayers := startGame(ctx, client, baseURL, hostAddress, []string{"player0001", "player0002", "player0003"})
+
+		wrongPhaseResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/players/%s/vote/kill?playerAddress=%s", baseURL, hostAddress, mafiaPlayers[0], civilianAddresses[0]))
+		Expect(err).ToNot(HaveOccurred(), "voting to kill during the day should not fail")
+		expectError(wrongPhaseResponse, http.StatusConflict, "invalid_phase", "voting to kill during the day")
+
+		invalidTargetResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/players/%s/vote/accuse?playerAddress=nobody", baseURL, hostAddress, civilianAddresses[0]))
+		Expect(err).ToNot(HaveOccurred(), "accusing a non-player should not fail")
+		expectError(invalidTargetResponse, http.StatusUnprocessableEntity, "invalid_target", "accusing a non-player")
+
+		outsiderResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/players/nobody/vote/accuse?playerAddress=%s", baseURL, hostAddress, civilianAddresses[0]))
+		Expect(err).ToNot(HaveOccurred(), "accusing as a non-player should not fail")
+		expectError(outsiderResponse, http.StatusForbidden, "forbidden", "accusing as a non-player")
+
+		accuseURL := fmt.Sprintf("%s/game/%s/players/%s/vote/accuse?playerAddress=%s", baseURL, hostAddress, civilianAddresses[0], mafiaPlayers[0])
+		firstAccuseResponse, err := client.R().SetContext(ctx).Post(accuseURL)
+		Expect(err).ToNot(HaveOccurred(), "accusing should not fail")
+		Expect(firstAccuseResponse.StatusCode()).To(Equal(http.StatusOK), "the first accusation should be accepted")
+
+		secondAccuseResponse, err := client.R().SetContext(ctx).Post(accuseURL)
+		Expect(err).ToNot(HaveOccurred(), "accusing twice should not fail")
+		expectError(secondAccuseResponse, http.StatusConflict, "conflict", "accusing twice")
+
+		forcedRolesHostAddress := "unassignablehost"
+		forcedRolesResponse, err := client.R().SetContext(ctx).SetBody(map[string]any{"forcedRoles": map[string]int{"nobody": 1}}).Post(fmt.Sprintf("%s/game/%s", baseURL, forcedRolesHostAddress))
+		Expect(err).ToNot(HaveOccurred(), "initializing a game with forced roles should not fail")
+		Expect(forcedRolesResponse.StatusCode()).To(Equal(http.StatusOK), "the game with forced roles should be initialized")
+		for _, playerAddress := range []string{"player0001", "player0002", "player0003"} {
+			joinResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/join?playerAddress=%s&playerNickname=%sNick", baseURL, forcedRolesHostAddress, playerAddress, playerAddress))
+			Expect(err).ToNot(HaveOccurred(), "%s joining game should not fail", playerAddress)
+			Expect(joinResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code when player '%s' joined game", playerAddress)
+		}
+		unassignableResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/start", baseURL, forcedRolesHostAddress))
+		Expect(err).ToNot(HaveOccurred(), "starting a game whose forced roles cannot be assigned should not fail")
+		expectError(unassignableResponse, http.StatusConflict, "conflict", "starting a game whose forced roles cannot be assigned")
+
+		timeoutConfig := config.Default()
+		timeoutConfig.WaitTimeout = 100 * time.Millisecond
+		timeoutServer, err := server.NewServer(timeoutConfig, logger)
+		Expect(err).ToNot(HaveOccurred(), "building the server with a short wait timeout should not fail")
+		timeoutHTTPServer := httptest.NewServer(timeoutServer)
+		DeferCleanup(timeoutHTTPServer.Close)
+
+		timeoutInitializeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s", timeoutHTTPServer.URL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "initializing the game should not fail")
+		Expect(timeoutInitializeResponse.StatusCode()).To(Equal(http.StatusOK), "the game should be initialized")
+		timedOutResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/start/wait", timeoutHTTPServer.URL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "waiting for the game to start should not fail")
+		expectError(timedOutResponse, http.StatusRequestTimeout, "wait_timeout", "waiting past the wait timeout")
+	})
+
+	It("plays a game through the client as a game engine", func() {
+		clientOptions := gameclient.DefaultOptions()
+		clientOptions.AdminToken = adminToken
+		var engine game.Engine = gameclient.New(baseURL, clientOptions)
+		hostAddress := "clienthost"
+		playerAddresses := []string{hostAddress, "clientplayer1", "clientplayer2", "clientplayer3", "clientplayer4"}
+		mafiaAddress := playerAddresses[1]
+
+		config := game.DefaultGameConfig()
+		config.AllowGodView = true
+		config.ForcedRoles = map[string]game.PlayerRole{mafiaAddress: game.PlayerRoleMafia}
+		Expect(engine.InitializeGame(ctx, hostAddress, config)).To(Succeed(), "initializing the game should not fail")
+		Expect(engine.InitializeGame(ctx, hostAddress, nil)).To(MatchError(game.ErrConflict), "initializing the game twice should be a conflict")
+
+		for _, playerAddress := range playerAddresses[:2] {
+			Expect(engine.JoinGame(ctx, hostAddress, playerAddress, playerAddress+"Nick")).To(Succeed(), "'%s' joining the game should not fail", playerAddress)
+		}
+
+		err := engine.StartGame(ctx, hostAddress)
+		Expect(err).To(MatchError(game.ErrConflict), "starting the game without enough players should be a conflict")
+		var clientErr *gameclient.Error
+		Expect(errors.As(err, &clientErr)).To(BeTrue(), "the failure should be described by the server")
+		Expect(clientErr.StatusCode).To(Equal(http.StatusConflict), "unexpected status code for starting the game too early")
+		Expect(clientErr.PlayersNeeded).To(Equal(1), "the number of players needed should be given")
+
+		for _, playerAddress := range playerAddresses[2:] {
+			Expect(engine.JoinGame(ctx, hostAddress, playerAddress, playerAddress+"Nick")).To(Succeed(), "'%s' joining the game should not fail", playerAddress)
+		}
+		Expect(engine.StartGame(ctx, hostAddress)).To(Succeed(), "starting the game should not fail")
+		Expect(engine.WaitForGameStart(ctx, hostAddress)).To(Succeed(), "the game should have started")
+
+		players, err := engine.GetPlayers(ctx, hostAddress)
+		Expect(err).ToNot(HaveOccurred(), "getting the players should not fail")
+		Expect(players).To(HaveLen(len(playerAddresses)), "every player should be listed")
+		for _, player := range players {
+			expectedRole := game.PlayerRoleCivilian
+			if player.PlayerAddress == mafiaAddress {
+				expectedRole = game.PlayerRoleMafia
+			}
+			Expect(player.PlayerRole).To(Equal(expectedRole), "unexpected role for player '%s'", player.PlayerAddress)
+			Expect(player.PlayerNickname).To(Equal(player.PlayerAddress+"Nick"), "unexpected nickname for player '%s'", player.PlayerAddress)
+		}
+
+		missingPlayer, err := engine.GetPlayer(ctx, hostAddress, "nobody")
+		Expect(err).ToNot(HaveOccurred(), "getting a player who is not in the game should not fail")
+		Expect(missingPlayer).To(BeNil(), "no player should be returned for someone who is not in the game")
+
+		_, err = engine.GetPlayer(ctx, "missinghost", hostAddress)
+		Expect(err).To(MatchError(game.ErrNotFound), "getting a player of a game that does not exist should fail")
+
+		Expect(engine.AddSpectator(ctx, hostAddress, "clientspectator", true)).To(Succeed(), "adding a spectator should not fail")
+		spectator, err := engine.GetSpectator(ctx, hostAddress, "clientspectator")
+		Expect(err).ToNot(HaveOccurred(), "getting the spectator should not fail")
+		Expect(spectator).To(Equal(&game.Spectator{SpectatorAddress: "clientspectator", GodView: true}), "unexpected spectator")
+
+		Expect(engine.VoteToKill(ctx, hostAddress, mafiaAddress, hostAddress)).To(MatchError(game.ErrInvalidPhase), "voting to kill during the day should not be allowed")
+		Expect(engine.AccuseAsMafia(ctx, hostAddress, hostAddress, "nobody")).To(MatchError(game.ErrInvalidTarget), "accusing someone who is not in the game should not be allowed")
+		Expect(engine.AccuseAsMafia(ctx, hostAddress, "nobody", hostAddress)).To(MatchError(game.ErrForbidden), "someone who is not in the game should not be able to accuse")
+		for _, playerAddress := range playerAddresses {
+			if playerAddress != mafiaAddress {
+				Expect(engine.AccuseAsMafia(ctx, hostAddress, playerAddress, mafiaAddress)).To(Succeed(), "'%s' accusing the Mafia should not fail", playerAddress)
+			}
+		}
+
+		votes, err := engine.GetVotes(ctx, hostAddress)
+		Expect(err).ToNot(HaveOccurred(), "getting the votes should not fail")
+		Expect(votes.MafiaAccusations).To(HaveKeyWithValue(hostAddress, mafiaAddress), "the host's accusation should be counted")
+		_, err = gameclient.New(baseURL, nil).GetVotes(ctx, hostAddress)
+		Expect(err).To(MatchError(game.ErrForbidden), "getting the votes without the admin token should be forbidden")
+
+		waitedPhaseExecution := make(chan *game.PhaseExecution, 1)
+		go func() {
+			defer GinkgoRecover()
+			phaseExecution, err := engine.WaitForPhaseExecution(ctx, hostAddress)
+			Expect(err).ToNot(HaveOccurred(), "waiting for the phase to be executed should not fail")
+			waitedPhaseExecution <- phaseExecution
+		}()
+
+		// give the waiter time to start waiting
+		time.Sleep(250 * time.Millisecond)
+
+		phaseExecution, err := engine.ExecutePhase(ctx, hostAddress)
+		Expect(err).ToNot(HaveOccurred(), "executing the phase should not fail")
+		Expect(phaseExecution.ConvictedPlayers).To(ConsistOf(mafiaAddress), "the Mafia should have been convicted")
+		Expect(phaseExecution.PhaseOutcome).To(Equal(game.PhaseOutcomeCivilianVictory), "the civilians should have won")
+		Eventually(waitedPhaseExecution).Should(Receive(Equal(phaseExecution)), "the waiting player should have been told of the phase execution")
+
+		Expect(engine.FinishGame(ctx, hostAddress)).To(Succeed(), "finishing the game should not fail")
+
+		archivedGames, err := engine.GetArchivedGames(ctx)
+		Expect(err).ToNot(HaveOccurred(), "getting the archived games should not fail")
+		Expect(archivedGames).To(HaveLen(1), "the finished game should be archived")
+		Expect(archivedGames[0].HostAddress).To(Equal(hostAddress), "unexpected host of the archived game")
+		Expect(archivedGames[0].Players).To(ContainElement(HaveField("PlayerRole", game.PlayerRoleMafia)), "the roles of archived players should be revealed")
+
+		missingArchivedGame, err := engine.GetArchivedGame(ctx, "nope")
+		Expect(err).ToNot(HaveOccurred(), "getting an archived game that does not exist should not fail")
+		Expect(missingArchivedGame).To(BeNil(), "no archived game should be returned for an unknown ID")
+	})
+
+	It("exports metrics describing games and requests", func() {
+		scrape := func() string {
+			metricsResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/metrics", baseURL))
+			Expect(err).ToNot(HaveOccurred(), "scraping the metrics should not fail")
+			Expect(metricsResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code scraping the metrics")
+			return string(metricsResponse.Body())
+		}
+		engine := gameclient.New(baseURL, nil)
+		hostAddress := "metricshost"
+
+		civilianAddresses, mafiaPlayers := startGame(ctx, client, baseURL, hostAddress, []string{hostAddress, "player0001", "player0002", "player0003", "player0004"})
+		Expect(mafiaPlayers).To(HaveLen(1), "there should be a single member of the Mafia")
+
+		waitedPhaseExecution := make(chan *game.PhaseExecution, 1)
+		go func() {
+			defer GinkgoRecover()
+			phaseExecution, err := engine.WaitForPhaseExecution(ctx, hostAddress)
+			Expect(err).ToNot(HaveOccurred(), "waiting for the phase to be executed should not fail")
+			waitedPhaseExecution <- phaseExecution
+		}()
+		Eventually(scrape).Should(ContainSubstring(`mafia_waiters{wait="phase"} 1`), "the blocked waiter should be counted")
+
+		for _, civilianAddress := range civilianAddresses {
+			Expect(engine.AccuseAsMafia(ctx, hostAddress, civilianAddress, mafiaPlayers[0])).To(Succeed(), "'%s' accusing the Mafia should not fail", civilianAddress)
+		}
+		phaseExecution, err := engine.ExecutePhase(ctx, hostAddress)
+		Expect(err).ToNot(HaveOccurred(), "executing the phase should not fail")
+		Expect(phaseExecution.PhaseOutcome).To(Equal(game.PhaseOutcomeCivilianVictory), "the civilians should have won")
+		Eventually(waitedPhaseExecution).Should(Receive(), "the waiter should have been told of the phase execution")
+		Expect(engine.FinishGame(ctx, hostAddress)).To(Succeed(), "finishing the game should not fail")
+
+		Expect(engine.InitializeGame(ctx, "metricscancelled", nil)).To(Succeed(), "initializing the game to be cancelled should not fail")
+		Expect(engine.CancelGame(ctx, "metricscancelled")).To(Succeed(), "cancelling the game should not fail")
+
+		Expect(engine.InitializeGame(ctx, "metricsactive", nil)).To(Succeed(), "initializing the active game should not fail")
+		for _, playerAddress := range []string{"metricsactive", "player0005"} {
+			Expect(engine.JoinGame(ctx, "metricsactive", playerAddress, playerAddress+"Nick")).To(Succeed(), "'%s' joining the active game should not fail", playerAddress)
+		}
+
+		exportedMetrics := scrape()
+		for _, expectedLine := range []string{
+			`mafia_active_games 1`,
+			`mafia_game_players{host_address="metricsactive"} 2`,
+			`mafia_games_started_total 1`,
+			`mafia_games_finished_total{outcome="civilian_victory"} 1`,
+			`mafia_games_finished_total{outcome="mafia_victory"} 0`,
+			`mafia_games_finished_total{outcome="cancelled"} 1`,
+			`mafia_phase_executions_total{time_of_day="day"} 1`,
+			`mafia_votes_total{action="accuse"} 4`,
+			`mafia_votes_total{action="kill"} 0`,
+			`mafia_waiters{wait="phase"} 0`,
+			`mafia_http_request_duration_seconds_count{method="POST",route="/game/:hostAddress/phase/execute",status="200"} 1`,
+		} {
+			Expect(exportedMetrics).To(ContainSubstring(expectedLine+"\n"), "the metrics should include '%s'", expectedLine)
+		}
+		Expect(exportedMetrics).ToNot(ContainSubstring(`host_address="metricshost"`), "finished games should no longer be counted as active")
+	})
+
+	It("logs each request with its request ID", func() {
+		var logOutput bytes.Buffer
+		jsonLogger := logging.NewLogger(io.MultiWriter(&logOutput, GinkgoWriter), config.LogFormatJSON, config.LogLevelInfo)
+		loggedServer, err := server.NewServer(config.Default(), jsonLogger)
+		Expect(err).ToNot(HaveOccurred(), "building the server should not fail")
+
+		generatedRecorder := httptest.NewRecorder()
+		loggedServer.ServeHTTP(generatedRecorder, httptest.NewRequest(http.MethodGet, "/games", nil))
+		Expect(generatedRecorder.Header().Get("X-Request-ID")).ToNot(BeEmpty(), "a request ID should be generated when none is given")
+
+		suppliedRecorder := httptest.NewRecorder()
+		suppliedRequest := httptest.NewRequest(http.MethodPost, "/game/loggedhost", nil)
+		suppliedRequest.Header.Set("X-Request-ID", "supplied-request-id")
+		loggedServer.ServeHTTP(suppliedRecorder, suppliedRequest)
+		Expect(suppliedRecorder.Code).To(Equal(http.StatusOK), "unexpected status code initializing the game; response body was '%s'", suppliedRecorder.Body.String())
+		Expect(suppliedRecorder.Header().Get("X-Request-ID")).To(Equal("supplied-request-id"), "the request ID given by the caller should be returned")
+
+		var messages []map[string]any
+		decoder := json.NewDecoder(&logOutput)
+		for decoder.More() {
+			var message map[string]any
+			Expect(decoder.Decode(&message)).To(Succeed(), "every log message should be JSON")
+			messages = append(messages, message)
+		}
+
+		Expect(messages).To(ContainElement(And(
+			HaveKeyWithValue("msg", "initialized game"),
+			HaveKeyWithValue("hostAddress", "loggedhost"),
+			HaveKeyWithValue("requestID", "supplied-request-id"),
+		)), "the engine should log with the context of the request")
+		Expect(messages).To(ContainElement(And(
+			HaveKeyWithValue("msg", "handled request"),
+			HaveKeyWithValue("route", "/game/:hostAddress"),
+			HaveKeyWithValue("status", BeNumerically("==", http.StatusOK)),
+			HaveKeyWithValue("hostAddress", "loggedhost"),
+			HaveKeyWithValue("requestID", "supplied-request-id"),
+		)), "the request should be logged once it was handled")
+	})
+
+	It("releases long polls when shutting down", func() {
+		serverConfig := config.Default()
+		serverConfig.SnapshotPath = filepath.Join(GinkgoT().TempDir(), "snapshot.json")
+		shutdownServer, err := server.NewServer(serverConfig, logger)
+		Expect(err).ToNot(HaveOccurred(), "building the server should not fail")
+		shutdownHTTPServer := httptest.NewServer(shutdownServer)
+		DeferCleanup(shutdownHTTPServer.Close)
+		shutdownURL := shutdownHTTPServer.URL
+
+		hostAddress := "shutdownhost"
+		initializeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s", shutdownURL, hostAddress))
+		Expect(err).ToNot(HaveOccurred(), "initializing the game should not fail")
+		Expect(initializeResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code initializing the game")
+
+		waitResponses := make(chan *resty.Response, 1)
+		go func() {
+			defer GinkgoRecover()
+			waitResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/start/wait", shutdownURL, hostAddress))
+			Expect(err).ToNot(HaveOccurred(), "waiting for the game to start should not fail")
+			waitResponses <- waitResponse
+		}()
+
+		// give the waiter time to start waiting
+		time.Sleep(250 * time.Millisecond)
+
+		Expect(shutdownServer.Shutdown(ctx)).To(Succeed(), "shutting down should not fail")
+
+		var waitResponse *resty.Response
+		Eventually(waitResponses).Should(Receive(&waitResponse), "the long poll should be released by the shutdown")
+		Expect(waitResponse.StatusCode()).To(Equal(http.StatusServiceUnavailable), "unexpected status code of the released long poll")
+		Expect(waitResponse.Header().Get("Retry-After")).ToNot(BeEmpty(), "the caller should be told when to retry")
+		Expect(string(waitResponse.Body())).To(ContainSubstring(`"code":"shutting_down"`), "the failure should be described as a shutdown")
+
+		_, err = gameclient.New(shutdownURL, &gameclient.Options{}).WaitForPhaseExecution(ctx, hostAddress)
+		Expect(err).To(MatchError(game.ErrShuttingDown), "long polls made after the shutdown should be released immediately")
+
+		// requests other than long polls are still handled while they are drained, and must not be held up by the released waiter
+		joinAndStartGame(ctx, client, shutdownURL, hostAddress, []string{"shutdown0001", "shutdown0002", "shutdown0003"})
+
+		Expect(shutdownServer.WriteSnapshot(ctx)).To(Succeed(), "writing the snapshot should not fail")
+		snapshotJSON, err := os.ReadFile(serverConfig.SnapshotPath)
+		Expect(err).ToNot(HaveOccurred(), "the snapshot should have been written")
+		var snapshot game.Snapshot
+		Expect(json.Unmarshal(snapshotJSON, &snapshot)).To(Succeed(), "the snapshot should be JSON")
+		Expect(snapshot.Games).To(ConsistOf(And(HaveField("HostAddress", hostAddress), HaveField("Started", true))), "the started game should be in the snapshot")
+	})
+
+	It("retries long polls from the client that are dropped", func() {
+		engine := gameclient.New(baseURL, &gameclient.Options{MaxRetries: 20, RetryWait: 50 * time.Millisecond})
+		setFaults := func(method string, config map[string]any) {
+			faultsResponse, err := client.R().SetContext(ctx).SetBody(config).Execute(method, fmt.Sprintf("%s/admin/faults", baseURL))
+			Expect(err).ToNot(HaveOccurred(), "configuring faults should not fail")
+			Expect(faultsResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code configuring faults; response body was '%s'", string(faultsResponse.Body()))
+		}
+
+		setFaults(http.MethodPut, map[string]any{
+			"routes": map[string]any{
+				"GET /games/wait": map[string]any{"dropRate": 1},
+			},
+		})
+
+		lobbyUpdated := make(chan *game.Lobby, 1)
+		go func() {
+			defer GinkgoRecover()
+			lobby, err := engine.WaitForLobbyUpdate(ctx, 0)
+			Expect(err).ToNot(HaveOccurred(), "waiting for the lobby to change should not fail")
+			lobbyUpdated <- lobby
+		}()
+
+		Expect(engine.InitializeGame(ctx, "retryhost", nil)).To(Succeed(), "initializing the game should not fail")
+		Consistently(lobbyUpdated, 200*time.Millisecond).ShouldNot(Receive(), "the long poll should be dropped while the fault is injected")
+
+		setFaults(http.MethodDelete, nil)
+		var lobby *game.Lobby
+		Eventually(lobbyUpdated).Should(Receive(&lobby), "the long poll should have been retried once the fault was cleared")
+		Expect(lobby.Games).To(ContainElement(HaveField("HostAddress", "retryhost")), "the new game should be listed")
+
+		noRetryEngine := gameclient.New(baseURL, &gameclient.Options{})
+		setFaults(http.MethodPut, map[string]any{
+			"global": map[string]any{"failureRate": 1, "failureStatuses": []int{http.StatusServiceUnavailable}},
+		})
+		_, err := noRetryEngine.GetLobby(ctx)
+		Expect(err).To(HaveOccurred(), "the failure should be returned once the retries are exhausted")
+		var clientErr *gameclient.Error
+		Expect(errors.As(err, &clientErr)).To(BeTrue(), "the failure should be described by the server")
+		Expect(clientErr.StatusCode).To(Equal(http.StatusServiceUnavailable), "unexpected status code of the injected failure")
+	})
+})
+
+// startGame initializes and starts a game with the given players, returning the addresses of the civilians and Mafia members, respectively
+func startGame(ctx context.Context, client resty.Client, baseURL string, hostAddress string, playerAddresses []string) ([]string, []string) {
+	initializeResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s", baseURL, hostAddress))
+	Expect(err).ToNot(HaveOccurred(), "initializing the game should not fail")
+	Expect(initializeResponse.StatusCode()).To(Equal(http.StatusOK), "the game initialization response should signal success")
+
+	return joinAndStartGame(ctx, client, baseURL, hostAddress, playerAddresses)
+}
+
+// joinAndStartGame joins the given players to an already-initialized game and starts it, returning the addresses of the civilians and Mafia members, respectively
+func joinAndStartGame(ctx context.Context, client resty.Client, baseURL string, hostAddress string, playerAddresses []string) ([]string, []string) {
+	for _, playerAddress := range playerAddresses {
+		joinResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/join?playerAddress=%s&playerNickname=%sNick", baseURL, hostAddress, playerAddress, playerAddress))
+		Expect(err).ToNot(HaveOccurred(), "%s joining game should not fail", playerAddress)
+		Expect(joinResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected status code when player '%s' joined game", playerAddress)
+	}
+
+	startResponse, err := client.R().SetContext(ctx).Post(fmt.Sprintf("%s/game/%s/start", baseURL, hostAddress))
+	Expect(err).ToNot(HaveOccurred(), "starting the game should not fail")
+	Expect(startResponse.StatusCode()).To(Equal(http.StatusOK), "unexpected response to starting game")
+
+	var civilianAddresses []string
+	var mafiaPlayers []string
+	for _, playerAddress := range playerAddresses {
+		playerInfoResponse, err := client.R().SetContext(ctx).Get(fmt.Sprintf("%s/game/%s/players/%s", baseURL, hostAddress, playerAddress))
+		Expect(err).ToNot(HaveOccurred(), "getting info for player '%s' should not have failed", playerAddress)
+
+		var infoResponse map[string]any
+		Expect(json.Unmarshal(playerInfoResponse.Body(), &infoResponse)).ToNot(HaveOccurred(), "unmarshalling the player '%s' info response from JSON should not fail", playerAddress)
+		switch infoResponse["playerRole"] {
+		case float64(0):
+			civilianAddresses = append(civilianAddresses, playerAddress)
+		case float64(1):
+			mafiaPlayers = append(mafiaPlayers, playerAddress)
 		}
 	}
+
+	return civilianAddresses, mafiaPlayers
+}
+
+// pathParamPattern matches the parameters of gin routes so that they can be rewritten in the form used by OpenAPI
+var pathParamPattern = regexp.MustCompile(`:(\w+)`)
+
+type lobbyResponse struct {
+	Version int                  `json:"version"`
+	Games   []*lobbyGameResponse `json:"games"`
+}
+
+type lobbyGameResponse struct {
+	HostAddress  string `json:"hostAddress"`
+	HostNickname string `json:"hostNickname"`
+	PlayerCount  int    `json:"playerCount"`
+	MinPlayers   int    `json:"minPlayers"`
+	Capacity     int    `json:"capacity"`
 }
 
 type phaseExecutionResponse struct {
@@ -283,4 +1308,5 @@ type phaseExecutionResponse struct {
 	CurrentPhase     int      `json:"currentPhase"`
 	KilledPlayers    []string `json:"killedPlayers"`
 	ConvictedPlayers []string `json:"convictedPlayers"`
+	ForfeitedPlayers []string `json:"forfeitedPlayers"`
 }
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteSnapshot writes the state of every game as JSON to the configured snapshot path; if no path is configured, this does nothing.
+// This should be called once every request has been drained, so that the snapshot is the final state of the games.
+func (s *Server) WriteSnapshot(ctx context.Context) error {
+	if s.snapshotPath == "" {
+		return nil
+	}
+
+	snapshot, err := s.shutdownEngine.Snapshot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot games: %w", err)
+	}
+
+	snapshotJSON, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	// write to a temporary file first so that a failure part-way through does not clobber an earlier snapshot
+	tempFile, err := os.CreateTemp(filepath.Dir(s.snapshotPath), filepath.Base(s.snapshotPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(snapshotJSON); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+
+	if err := os.Rename(tempFile.Name(), s.snapshotPath); err != nil {
+		return fmt.Errorf("failed to move snapshot file into place: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "wrote snapshot of games", "snapshotPath", s.snapshotPath, "activeGames", len(snapshot.Games), "archivedGames", len(snapshot.ArchivedGames))
+
+	return nil
+}